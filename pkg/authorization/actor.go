@@ -0,0 +1,82 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrImpersonated    = errors.New("impersonated token not allowed")
+	ErrNotImpersonated = errors.New("impersonated token required")
+)
+
+// Actor is a party acting on behalf of the subject of the token,
+// e.g. an administrator impersonating a user or a service using token exchange (`act` claim of RFC 8693).
+type Actor struct {
+	Subject string
+	Issuer  string
+}
+
+// ActorCtx is an optional extension of [Ctx] providing information about impersonation and delegation.
+// It is required by [WithoutImpersonation] and [WithImpersonation].
+type ActorCtx interface {
+	// ActorChain returns the actors of the token, starting with the current actor followed by prior actors.
+	// It is empty if the subject of the token is acting on their own.
+	ActorChain() []Actor
+}
+
+// ActorChain returns the actors acting on behalf of the authorized user (see [ActorCtx]).
+// In case of an unauthorized caller, a caller acting on their own or a context not implementing [ActorCtx], it is empty.
+func ActorChain(ctx context.Context) []Actor {
+	actorCtx, ok := Context[Ctx](ctx).(ActorCtx)
+	if !ok {
+		return nil
+	}
+	return actorCtx.ActorChain()
+}
+
+// IsImpersonated returns if the token of the authorized user was issued to another party acting on their behalf.
+func IsImpersonated(ctx context.Context) bool {
+	return len(ActorChain(ctx)) > 0
+}
+
+// WithoutImpersonation rejects tokens issued to an actor on behalf of the user with an [ErrImpersonated].
+func WithoutImpersonation() CheckOption {
+	return withActorCheck(func(actors []Actor) error {
+		if len(actors) > 0 {
+			return fmt.Errorf("%w: acting party `%s`", ErrImpersonated, actors[0].Subject)
+		}
+		return nil
+	})
+}
+
+// WithImpersonation requires the token to be issued to an actor on behalf of the user.
+// If subjects are provided, the current actor must be one of them.
+// Otherwise, an [ErrNotImpersonated] is returned.
+func WithImpersonation(subjects ...string) CheckOption {
+	return withActorCheck(func(actors []Actor) error {
+		if len(actors) == 0 {
+			return ErrNotImpersonated
+		}
+		if len(subjects) > 0 && !slices.Contains(subjects, actors[0].Subject) {
+			return fmt.Errorf("%w: acting party `%s` not allowed", ErrNotImpersonated, actors[0].Subject)
+		}
+		return nil
+	})
+}
+
+// withActorCheck adds a check requiring the authorization context to implement [ActorCtx].
+// If it does not, an [ErrUnsupportedContext] is returned.
+func withActorCheck(check func(actors []Actor) error) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			actorCtx, ok := authCtx.(ActorCtx)
+			if !ok {
+				return ErrUnsupportedContext
+			}
+			return check(actorCtx.ActorChain())
+		})
+	}
+}
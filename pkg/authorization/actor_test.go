@@ -0,0 +1,76 @@
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonationChecks(t *testing.T) {
+	impersonated := &testCtx{
+		isAuthorized: true,
+		actors:       []Actor{{Subject: "admin", Issuer: "issuer"}, {Subject: "service"}},
+	}
+	notImpersonated := &testCtx{isAuthorized: true}
+	tests := []struct {
+		name    string
+		authCtx *testCtx
+		options []CheckOption
+		wantErr error
+	}{
+		{
+			name:    "without impersonation",
+			authCtx: notImpersonated,
+			options: []CheckOption{WithoutImpersonation()},
+		},
+		{
+			name:    "without impersonation, impersonated",
+			authCtx: impersonated,
+			options: []CheckOption{WithoutImpersonation()},
+			wantErr: ErrImpersonated,
+		},
+		{
+			name:    "with impersonation",
+			authCtx: impersonated,
+			options: []CheckOption{WithImpersonation()},
+		},
+		{
+			name:    "with impersonation, not impersonated",
+			authCtx: notImpersonated,
+			options: []CheckOption{WithImpersonation()},
+			wantErr: ErrNotImpersonated,
+		},
+		{
+			name:    "with impersonation by actor",
+			authCtx: impersonated,
+			options: []CheckOption{WithImpersonation("support", "admin")},
+		},
+		{
+			name:    "with impersonation by other actor",
+			authCtx: impersonated,
+			options: []CheckOption{WithImpersonation("service")},
+			wantErr: ErrNotImpersonated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authCtx, err := newTestAuthorizer(tt.authCtx).CheckAuthorization(context.Background(), "Bearer token", tt.options...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, NewErrorPermissionDenied(tt.wantErr))
+				return
+			}
+			assert.NoError(t, err)
+			ctx := WithAuthContext(context.Background(), authCtx)
+			assert.Equal(t, tt.authCtx.actors, ActorChain(ctx))
+			assert.Equal(t, len(tt.authCtx.actors) > 0, IsImpersonated(ctx))
+		})
+	}
+}
+
+func TestImpersonationChecks_UnsupportedContext(t *testing.T) {
+	authCtx, err := newTestAuthorizer(newTestBasicCtx()).CheckAuthorization(context.Background(), "Bearer token", WithoutImpersonation())
+	assert.ErrorIs(t, err, NewErrorPermissionDenied(ErrUnsupportedContext))
+	assert.Empty(t, ActorChain(WithAuthContext(context.Background(), authCtx)))
+	assert.Empty(t, ActorChain(context.Background()))
+}
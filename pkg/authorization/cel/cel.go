@@ -0,0 +1,261 @@
+// Package cel provides authorization checks backed by [CEL] expressions,
+// which are evaluated against the claims of the token and the attributes of the request.
+//
+// The following variables are available in an expression:
+//   - `claims` (map(string, dyn)): all claims of the token (see [ClaimsCtx])
+//   - `roles` (map(string, dyn)): the roles of the `urn:zitadel:iam:org:project:roles` claim,
+//     mapping each role to the organizations (id to primary domain) it was granted in
+//   - `org_id` (string): the organization of the user (see [authorization.Ctx.OrganizationID])
+//   - `user_id` (string): the id of the user (see [authorization.Ctx.UserID])
+//   - `request` (map(string, string)): the `method` and `path` of the request.
+//     For gRPC calls the method is always `POST` and the path is the full method name (e.g. `/pkg.Service/Method`).
+//
+// For example:
+//
+//	'admin' in roles && org_id in roles['admin'] && request.method == 'DELETE'
+//
+// [CEL]: https://cel.dev
+package cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	celgo "github.com/google/cel-go/cel"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+)
+
+const (
+	VariableClaims  = "claims"
+	VariableRoles   = "roles"
+	VariableOrgID   = "org_id"
+	VariableUserID  = "user_id"
+	VariableRequest = "request"
+)
+
+var (
+	ErrInvalidPolicy    = errors.New("invalid policy")
+	ErrUnknownPolicy    = errors.New("unknown policy")
+	ErrEvaluationFailed = authorization.ErrEvaluationFailed
+)
+
+var _ ClaimsCtx = (*oauth.IntrospectionContext)(nil)
+
+// ClaimsCtx is an optional extension of [authorization.Ctx] providing all claims of the token
+// for the `claims` and `roles` variables. It is implemented by [oauth.IntrospectionContext].
+// For other contexts, the `claims` are empty and the `roles` are taken from [authorization.RolesCtx] if implemented.
+type ClaimsCtx interface {
+	AllClaims() map[string]any
+}
+
+var environment = sync.OnceValues(func() (*celgo.Env, error) {
+	return celgo.NewEnv(
+		celgo.Variable(VariableClaims, celgo.MapType(celgo.StringType, celgo.DynType)),
+		celgo.Variable(VariableRoles, celgo.MapType(celgo.StringType, celgo.DynType)),
+		celgo.Variable(VariableOrgID, celgo.StringType),
+		celgo.Variable(VariableUserID, celgo.StringType),
+		celgo.Variable(VariableRequest, celgo.MapType(celgo.StringType, celgo.StringType)),
+	)
+})
+
+// Policy is a compiled CEL expression, which must evaluate to a bool.
+type Policy struct {
+	name       string
+	expression string
+	program    celgo.Program
+}
+
+// Compile parses and type-checks the expression, so that invalid policies are detected at startup.
+// The name is used to identify the policy in case of a failure.
+// If the expression is invalid or does not evaluate to a bool, an [ErrInvalidPolicy] is returned.
+func Compile(name, expression string) (*Policy, error) {
+	env, err := environment()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if err := issues.Err(); err != nil {
+		return nil, fmt.Errorf("%w: `%s`: %w", ErrInvalidPolicy, name, err)
+	}
+	if ast.OutputType() != celgo.BoolType {
+		return nil, fmt.Errorf("%w: `%s`: expression must evaluate to bool, got %s", ErrInvalidPolicy, name, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("%w: `%s`: %w", ErrInvalidPolicy, name, err)
+	}
+	return &Policy{
+		name:       name,
+		expression: expression,
+		program:    program,
+	}, nil
+}
+
+// MustCompile is like [Compile] but panics if the expression is invalid.
+func MustCompile(name, expression string) *Policy {
+	policy, err := Compile(name, expression)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// Name returns the name of the policy.
+func (p *Policy) Name() string {
+	return p.name
+}
+
+// Expression returns the CEL expression of the policy.
+func (p *Policy) Expression() string {
+	return p.expression
+}
+
+// Evaluate evaluates the policy against the authorization context and the request
+// (see [authorization.Request]) of the context.
+func (p *Policy) Evaluate(ctx context.Context, authCtx authorization.Ctx) (bool, error) {
+	out, _, err := p.program.ContextEval(ctx, variables(ctx, authCtx))
+	if err != nil {
+		return false, fmt.Errorf("%w: `%s`: %w", ErrEvaluationFailed, p.name, err)
+	}
+	allowed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: `%s`: result is not a bool", ErrEvaluationFailed, p.name)
+	}
+	return allowed, nil
+}
+
+// WithPolicy requires the policy to evaluate to true.
+// If it evaluates to false, an [authorization.CheckErr] with the name of the policy wrapping an [authorization.ErrCheckFailed] is returned,
+// if the evaluation fails (e.g. because of a missing claim), an [ErrEvaluationFailed], which is not inverted by [authorization.Not].
+func WithPolicy(policy *Policy) authorization.CheckOption {
+	return func(checks *authorization.Check[authorization.Ctx]) {
+		ctx := checks.Context()
+		checks.Checks = append(checks.Checks, func(authCtx authorization.Ctx) error {
+			allowed, err := policy.Evaluate(ctx, authCtx)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return authorization.NewCheckErr(policy.name, authorization.ErrCheckFailed)
+			}
+			return nil
+		})
+	}
+}
+
+// PolicySet is a set of named policies, e.g. loaded from a policy file.
+type PolicySet struct {
+	policies map[string]*Policy
+}
+
+// NewPolicySet compiles the expressions of the provided map of policy names to expressions.
+// If any expression is invalid, an [ErrInvalidPolicy] is returned.
+func NewPolicySet(expressions map[string]string) (*PolicySet, error) {
+	policies := make(map[string]*Policy, len(expressions))
+	for name, expression := range expressions {
+		policy, err := Compile(name, expression)
+		if err != nil {
+			return nil, err
+		}
+		policies[name] = policy
+	}
+	return &PolicySet{
+		policies: policies,
+	}, nil
+}
+
+// ParsePolicySet creates a [PolicySet] from a YAML or JSON document
+// mapping policy names to CEL expressions, e.g.:
+//
+//	read-invoice: "'accountant' in roles || 'admin' in roles"
+//	delete-invoice: "'admin' in roles && request.method == 'DELETE'"
+func ParsePolicySet(data []byte) (*PolicySet, error) {
+	var expressions map[string]string
+	if err := yaml.Unmarshal(data, &expressions); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	return NewPolicySet(expressions)
+}
+
+// LoadPolicySet creates a [PolicySet] from a YAML or JSON file (see [ParsePolicySet]).
+func LoadPolicySet(path string) (*PolicySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicySet(data)
+}
+
+// Policy returns the policy with the provided name.
+// If there is none, an [ErrUnknownPolicy] is returned, which allows to validate the referenced policies at startup.
+func (s *PolicySet) Policy(name string) (*Policy, error) {
+	policy, ok := s.policies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: `%s`", ErrUnknownPolicy, name)
+	}
+	return policy, nil
+}
+
+// WithPolicy requires the policy with the provided name to evaluate to true (see [WithPolicy]).
+// If there is no such policy, every check fails with an [ErrUnknownPolicy].
+func (s *PolicySet) WithPolicy(name string) authorization.CheckOption {
+	policy, err := s.Policy(name)
+	if err != nil {
+		return func(checks *authorization.Check[authorization.Ctx]) {
+			checks.Checks = append(checks.Checks, func(authorization.Ctx) error {
+				return err
+			})
+		}
+	}
+	return WithPolicy(policy)
+}
+
+// variables returns the values of the variables available in an expression.
+func variables(ctx context.Context, authCtx authorization.Ctx) map[string]any {
+	claims := make(map[string]any)
+	roles := make(map[string]any)
+	if claimsCtx, ok := authCtx.(ClaimsCtx); ok {
+		for claim, value := range claimsCtx.AllClaims() {
+			claims[claim] = value
+		}
+		if projectRoles, ok := claims[oauth.ClaimProjectRoles].(map[string]any); ok {
+			roles = projectRoles
+		}
+	} else if rolesCtx, ok := authCtx.(authorization.RolesCtx); ok {
+		for _, role := range rolesCtx.Roles() {
+			roles[role] = map[string]any{}
+		}
+	}
+	return map[string]any{
+		VariableClaims:  claims,
+		VariableRoles:   roles,
+		VariableOrgID:   authCtx.OrganizationID(),
+		VariableUserID:  authCtx.UserID(),
+		VariableRequest: request(ctx),
+	}
+}
+
+// request returns the method and path of the [*http.Request] provided by [authorization.Request]
+// or of the [authorization.RequestInfo] (e.g. of a gRPC call).
+func request(ctx context.Context) map[string]string {
+	if req, ok := authorization.Request(ctx).(*http.Request); ok {
+		return map[string]string{
+			"method": req.Method,
+			"path":   req.URL.Path,
+		}
+	}
+	if info := authorization.RequestInfoFromContext(ctx); info != nil {
+		return map[string]string{
+			"method": info.Method,
+			"path":   info.Path,
+		}
+	}
+	return map[string]string{}
+}
@@ -0,0 +1,228 @@
+package cel_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/cel"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+	grpcmiddleware "github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+	httpmiddleware "github.com/zitadel/zitadel-go/v3/pkg/http/middleware"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+const policies = `
+read: "'reader' in roles || 'admin' in roles"
+delete: "'admin' in roles && org_id in roles['admin'] && request.method == 'DELETE'"
+own-org: "request.path.startsWith('/orgs/' + org_id + '/')"
+plan: "claims['urn:zitadel:iam:user:metadata'].plan == 'cHJv'"
+service: "request.path == '/pkg.Service/Get' && user_id == 'user'"
+`
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    error
+	}{
+		{
+			name:       "valid",
+			expression: "'admin' in roles && user_id == 'user'",
+		},
+		{
+			name:       "syntax error",
+			expression: "'admin' in roles &&",
+			wantErr:    cel.ErrInvalidPolicy,
+		},
+		{
+			name:       "undeclared variable",
+			expression: "project_id == 'project'",
+			wantErr:    cel.ErrInvalidPolicy,
+		},
+		{
+			name:       "no bool",
+			expression: "org_id",
+			wantErr:    cel.ErrInvalidPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := cel.Compile(tt.name, tt.expression)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, policy.Name())
+			assert.Equal(t, tt.expression, policy.Expression())
+		})
+	}
+}
+
+func TestParsePolicySet(t *testing.T) {
+	_, err := cel.ParsePolicySet([]byte("read: [invalid"))
+	assert.ErrorIs(t, err, cel.ErrInvalidPolicy)
+
+	_, err = cel.ParsePolicySet([]byte(`read: "org_id"`))
+	assert.ErrorIs(t, err, cel.ErrInvalidPolicy)
+
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(policies), 0o600))
+	set, err := cel.LoadPolicySet(path)
+	require.NoError(t, err)
+	_, err = set.Policy("read")
+	assert.NoError(t, err)
+	_, err = set.Policy("write")
+	assert.ErrorIs(t, err, cel.ErrUnknownPolicy)
+}
+
+func TestWithPolicy_HTTP(t *testing.T) {
+	set, err := cel.ParsePolicySet([]byte(policies))
+	require.NoError(t, err)
+	interceptor := httpmiddleware.New(newAuthorizer(t))
+
+	tests := []struct {
+		name     string
+		option   authorization.CheckOption
+		method   string
+		path     string
+		wantCode int
+	}{
+		{
+			name:     "role granted",
+			option:   set.WithPolicy("read"),
+			method:   http.MethodGet,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "role and method",
+			option:   set.WithPolicy("delete"),
+			method:   http.MethodDelete,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "wrong method",
+			option:   set.WithPolicy("delete"),
+			method:   http.MethodGet,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "own organization",
+			option:   set.WithPolicy("own-org"),
+			method:   http.MethodGet,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "other organization",
+			option:   set.WithPolicy("own-org"),
+			method:   http.MethodGet,
+			path:     "/orgs/org2/invoices",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "claims",
+			option:   set.WithPolicy("plan"),
+			method:   http.MethodGet,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "unknown policy",
+			option:   set.WithPolicy("write"),
+			method:   http.MethodGet,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "evaluation fails",
+			option:   cel.WithPolicy(cel.MustCompile("missing claim", "claims.missing == 'value'")),
+			method:   http.MethodGet,
+			path:     "/orgs/org1/invoices",
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := interceptor.RequireAuthorization(tt.option)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			request.Header.Set("Authorization", "Bearer token")
+			response := httptest.NewRecorder()
+
+			handler.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.wantCode, response.Code)
+		})
+	}
+}
+
+func TestWithPolicy_GRPC(t *testing.T) {
+	set, err := cel.ParsePolicySet([]byte(policies))
+	require.NoError(t, err)
+	interceptor := grpcmiddleware.New(newAuthorizer(t), map[string][]authorization.CheckOption{
+		"/pkg.Service/*": {set.WithPolicy("service")},
+	})
+
+	for method, wantCode := range map[string]codes.Code{
+		"/pkg.Service/Get":  codes.OK,
+		"/pkg.Service/List": codes.PermissionDenied,
+	} {
+		t.Run(method, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorization.HeaderName, "Bearer token"))
+			_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				},
+			)
+			assert.Equal(t, wantCode, status.Code(err))
+		})
+	}
+}
+
+func newAuthorizer(t *testing.T) *authorization.Authorizer[*oauth.IntrospectionContext] {
+	payload, err := json.Marshal(map[string]any{
+		"active":                                true,
+		"sub":                                   "user",
+		"urn:zitadel:iam:user:resourceowner:id": "org1",
+		"urn:zitadel:iam:user:metadata":         map[string]any{"plan": "cHJv"},
+		"urn:zitadel:iam:org:project:roles": map[string]any{
+			"admin": map[string]any{"org1": "org1.zitadel.cloud"},
+		},
+	})
+	require.NoError(t, err)
+	authCtx := new(oauth.IntrospectionContext)
+	require.NoError(t, json.Unmarshal(payload, &authCtx.IntrospectionResponse))
+	authorizer, err := authorization.New(context.Background(), zitadel.New("zitadel.invalid"),
+		func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*oauth.IntrospectionContext], error) {
+			return &verifier{ctx: authCtx}, nil
+		},
+	)
+	require.NoError(t, err)
+	return authorizer
+}
+
+type verifier struct {
+	ctx *oauth.IntrospectionContext
+}
+
+func (v *verifier) CheckAuthorization(context.Context, string) (*oauth.IntrospectionContext, error) {
+	return v.ctx, nil
+}
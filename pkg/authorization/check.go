@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"log/slog"
-	"regexp"
-	"strconv"
+	"net"
 	"strings"
+	"syscall"
 
 	"github.com/zitadel/oidc/v3/pkg/oidc"
 	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
@@ -46,6 +47,7 @@ type AuthorizationChecker[T Ctx] interface {
 type Authorizer[T Ctx] struct {
 	verifier Verifier[T]
 	logger   *slog.Logger
+	dpop     *dpopConfig
 }
 
 // compile-time check that Authorizer implements AuthorizationChecker
@@ -75,6 +77,11 @@ func New[T Ctx](ctx context.Context, zitadel *zitadel.Zitadel, initVerifier Veri
 	for _, option := range options {
 		option(authorizer)
 	}
+	for v := range unwrapVerifiers(verifier) {
+		if loggingVerifier, ok := v.(LoggingVerifier); ok {
+			loggingVerifier.SetLogger(authorizer.logger)
+		}
+	}
 	return authorizer, nil
 }
 
@@ -82,15 +89,26 @@ func New[T Ctx](ctx context.Context, zitadel *zitadel.Zitadel, initVerifier Veri
 func (a *Authorizer[T]) CheckAuthorization(ctx context.Context, token string, options ...CheckOption) (authCtx T, err error) {
 	a.logger.Log(ctx, slog.LevelDebug, "checking authorization")
 	var t T
-	if err := checkForEmptyorMalformedToken(token); err != nil {
+	scheme, accessToken, err := a.splitAuthorizationHeader(token)
+	if err != nil {
 		a.logger.Log(ctx, slog.LevelWarn, "no authorization header")
 		return t, NewErrorUnauthorized(err)
 	}
-	checks := new(Check[Ctx])
+	checks := &Check[Ctx]{ctx: ctx}
 	for _, option := range options {
 		option(checks)
 	}
-	authCtx, err = a.verifier.CheckAuthorization(ctx, token)
+	verifierToken := token
+	var proof *verifiedDPoPProof
+	if scheme == SchemeDPoP {
+		if proof, err = a.dpop.verifyProof(ctx, accessToken); err != nil {
+			a.logger.With("error", err).Log(ctx, slog.LevelWarn, "invalid DPoP proof")
+			return t, NewErrorUnauthorized(err)
+		}
+		// the verifiers expect the access token in the form of a bearer token
+		verifierToken = oidc.BearerToken + " " + accessToken
+	}
+	authCtx, err = a.verifier.CheckAuthorization(ctx, verifierToken)
 	if err != nil || !authCtx.IsAuthorized() {
 		if err != nil && isServerError(err) {
 			a.logger.With("error", err).Log(ctx, slog.LevelWarn, "service unavailable")
@@ -99,6 +117,19 @@ func (a *Authorizer[T]) CheckAuthorization(ctx context.Context, token string, op
 		a.logger.With("error", err).Log(ctx, slog.LevelWarn, "unauthorized")
 		return t, NewErrorUnauthorized(err)
 	}
+	if a.dpop != nil {
+		if err = a.dpop.checkBinding(authCtx, scheme, proof); err != nil {
+			a.logger.With("error", err, "user", authCtx.UserID()).Log(ctx, slog.LevelWarn, "unauthorized")
+			return t, NewErrorUnauthorized(err)
+		}
+	}
+	// the proof is only marked as used for a verified token, so it cannot be consumed by a request with an invalid token
+	if proof != nil {
+		if err = a.dpop.useProof(ctx, proof); err != nil {
+			a.logger.With("error", err, "user", authCtx.UserID()).Log(ctx, slog.LevelWarn, "invalid DPoP proof")
+			return t, NewErrorUnauthorized(err)
+		}
+	}
 	for _, c := range checks.Checks {
 		if err = c(authCtx); err != nil {
 			a.logger.With("error", err, "user", authCtx.UserID()).Log(ctx, slog.LevelWarn, "permission denied")
@@ -109,11 +140,70 @@ func (a *Authorizer[T]) CheckAuthorization(ctx context.Context, token string, op
 	return authCtx, nil
 }
 
+// splitAuthorizationHeader returns the scheme and access token of the authorization header.
+// The `DPoP` scheme is only accepted if enabled by [WithDPoP], which might also require it.
+func (a *Authorizer[T]) splitAuthorizationHeader(header string) (scheme, token string, err error) {
+	if a.dpop == nil {
+		if err := checkForEmptyorMalformedToken(header); err != nil {
+			return "", "", err
+		}
+	}
+	scheme, token, err = splitAuthorizationHeader(header)
+	if err != nil {
+		return "", "", err
+	}
+	if a.dpop != nil && a.dpop.required && scheme != SchemeDPoP {
+		return "", "", fmt.Errorf("%w: %w", ErrMissingToken, ErrDPoPRequired)
+	}
+	return scheme, token, nil
+}
+
 // Verifier defines the possible verification checks such as validation of the authorizationToken.
 type Verifier[T Ctx] interface {
 	CheckAuthorization(ctx context.Context, authorizationToken string) (T, error)
 }
 
+// LoggingVerifier is an optional extension of [Verifier] for implementations logging on their own.
+// The [Authorizer] provides its logger (see [WithLogger]) after the initialization.
+type LoggingVerifier interface {
+	SetLogger(logger *slog.Logger)
+}
+
+// WrappingVerifier is an optional extension of [Verifier] for implementations wrapping another [Verifier]
+// (e.g. [WithCertificateBinding]), so the wrapped verifier can be found regardless of the order of the wrappers
+// (see [FindVerifier]).
+type WrappingVerifier[T Ctx] interface {
+	Unwrap() Verifier[T]
+}
+
+// FindVerifier returns the first verifier of type V in the chain of the provided [Verifier] and its wrapped verifiers
+// (see [WrappingVerifier]), e.g. to configure it from an initializer wrapping the one of another package.
+func FindVerifier[V any, T Ctx](verifier Verifier[T]) (V, bool) {
+	for v := range unwrapVerifiers(verifier) {
+		if found, ok := v.(V); ok {
+			return found, true
+		}
+	}
+	var v V
+	return v, false
+}
+
+// unwrapVerifiers iterates over the verifier and the verifiers wrapped by it.
+func unwrapVerifiers[T Ctx](verifier Verifier[T]) iter.Seq[Verifier[T]] {
+	return func(yield func(Verifier[T]) bool) {
+		for verifier != nil {
+			if !yield(verifier) {
+				return
+			}
+			wrapping, ok := verifier.(WrappingVerifier[T])
+			if !ok {
+				return
+			}
+			verifier = wrapping.Unwrap()
+		}
+	}
+}
+
 // VerifierInitializer abstracts the initialization of a [Verifier] by providing the ZITADEL domain, port and if tls is set
 type VerifierInitializer[T Ctx] func(ctx context.Context, zitadel *zitadel.Zitadel) (Verifier[T], error)
 
@@ -121,6 +211,15 @@ type VerifierInitializer[T Ctx] func(ctx context.Context, zitadel *zitadel.Zitad
 // There will be options, e.g. caching and more in the near future.
 type Check[T Ctx] struct {
 	Checks []func(authCtx T) error
+	ctx    context.Context
+}
+
+// Context returns the context of the checked request, e.g. to access the request itself (see [Request]).
+func (c *Check[T]) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // CheckOption allows customization of the [Check] like additional permission requirements (e.g. roles)
@@ -139,26 +238,39 @@ func WithRole(role string) CheckOption {
 	}
 }
 
-// isServerError checks if an error indicates a 5xx server error from the introspection endpoint.
-// It looks for 5xx status codes in the error message or common server error indicators.
+// isServerError checks if an error indicates that the authorization service is (temporarily) unavailable:
+//   - a 5xx status code returned by ZITADEL (see [HTTPStatusErr])
+//   - a timeout of the request (including an exceeded context deadline)
+//   - a failure to connect such as a DNS resolution failure, a refused or reset connection
+//
+// Other errors of the request (e.g. a failed TLS verification or an invalid URL) are not considered server errors,
+// since they will not resolve by retrying. A canceled context is not considered a server error either,
+// since the caller aborted the request.
 func isServerError(err error) bool {
 	if err == nil {
 		return false
 	}
-
-	errorMsg := err.Error()
-
-	// Check for explicit 5xx status codes in the error message
-	// Note: When errors are wrapped with fmt.Errorf("%w", ...), the outer error's
-	// Error() method includes the wrapped error's message, so checking the outer
-	// error message is sufficient.
-	statusCodeRegex := regexp.MustCompile(`\b(5\d{2})\b`)
-	matches := statusCodeRegex.FindStringSubmatch(errorMsg)
-	if len(matches) > 1 {
-		if _, parseErr := strconv.Atoi(matches[1]); parseErr == nil {
-			return true
-		}
+	var statusErr *HTTPStatusErr
+	if errors.As(err, &statusErr) {
+		return statusErr.IsServerError()
 	}
-
-	return false
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
 }
@@ -2,12 +2,20 @@ package authorization
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
 )
 
 func TestAuthorizer_CheckAuthorization(t *testing.T) {
@@ -120,9 +128,20 @@ func TestAuthorizer_CheckAuthorization(t *testing.T) {
 type testVerifier[T Ctx] struct {
 	ctx T
 	err error
+	// token is the last token passed to the verifier
+	token string
 }
 
-func (t *testVerifier[T]) CheckAuthorization(_ context.Context, _ string) (T, error) {
+// newTestAuthorizer creates an [Authorizer] with a [testVerifier] returning the provided authorization context.
+func newTestAuthorizer[T Ctx](authCtx T) *Authorizer[T] {
+	return &Authorizer[T]{
+		verifier: &testVerifier[T]{ctx: authCtx},
+		logger:   slog.Default(),
+	}
+}
+
+func (t *testVerifier[T]) CheckAuthorization(_ context.Context, token string) (T, error) {
+	t.token = token
 	return t.ctx, t.err
 }
 
@@ -134,6 +153,16 @@ type testCtx struct {
 	isGrantedRoleInOrganization bool
 	isGrantedRoleInProject      bool
 	token                       string
+	// optional fields of the extensions of [Ctx] (e.g. [TokenCtx] or [RolesCtx])
+	scopes     []string
+	audience   []string
+	clientID   string
+	actors     []Actor
+	jkt        string
+	thumbprint string
+	instance   *zitadel.Zitadel
+	// roles per project and organization, the empty project being the requested project
+	roles map[string]map[string][]string
 }
 
 func (t *testCtx) SetToken(token string) {
@@ -186,6 +215,67 @@ func (t *testCtx) IsGrantedRoleInProject(_, _, _ string) bool {
 	return t.isGrantedRoleInProject
 }
 
+func (t *testCtx) HasScope(scope string) bool {
+	return slices.Contains(t.scopes, scope)
+}
+
+func (t *testCtx) HasAudience(audience string) bool {
+	return slices.Contains(t.audience, audience)
+}
+
+func (t *testCtx) GetClientID() string {
+	return t.clientID
+}
+
+func (t *testCtx) ActorChain() []Actor {
+	return t.actors
+}
+
+func (t *testCtx) Roles() []string {
+	var roles []string
+	for _, orgRoles := range t.roles[""] {
+		roles = append(roles, orgRoles...)
+	}
+	return roles
+}
+
+func (t *testCtx) RolesInOrganization(organizationID string) []string {
+	return t.roles[""][organizationID]
+}
+
+func (t *testCtx) RolesInProject(projectID string) []string {
+	var roles []string
+	for _, orgRoles := range t.roles[projectID] {
+		roles = append(roles, orgRoles...)
+	}
+	return roles
+}
+
+func (t *testCtx) JWKThumbprint() string {
+	return t.jkt
+}
+
+func (t *testCtx) CertificateThumbprint() string {
+	return t.thumbprint
+}
+
+func (t *testCtx) SetInstance(instance *zitadel.Zitadel) {
+	t.instance = instance
+}
+
+func (t *testCtx) Instance() *zitadel.Zitadel {
+	return t.instance
+}
+
+// testBasicCtx provides only the methods of [Ctx], e.g. to test checks requiring an extension of it.
+type testBasicCtx struct {
+	Ctx
+}
+
+func newTestBasicCtx() *testBasicCtx {
+	return &testBasicCtx{Ctx: &testCtx{isAuthorized: true}}
+}
+
 func TestCheckForEmptyorMalformedToken(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -296,35 +386,35 @@ func TestAuthorizer_CheckAuthorization_ServerError(t *testing.T) {
 	}{
 		{
 			name:           "503 Service Unavailable error returns ServiceUnavailableErr",
-			verifierErr:    fmt.Errorf("token introspection failed: http status not ok: 503 Service Unavailable"),
+			verifierErr:    NewErrorHTTPStatus(503, fmt.Errorf("token introspection failed: http status not ok: 503 Service Unavailable")),
 			wantServiceErr: true,
 			wantUnauthErr:  false,
 			description:    "503 errors should be returned as ServiceUnavailableErr, not UnauthorizedErr",
 		},
 		{
 			name:           "500 Internal Server Error returns ServiceUnavailableErr",
-			verifierErr:    fmt.Errorf("token introspection failed: http status not ok: 500 Internal Server Error"),
+			verifierErr:    NewErrorHTTPStatus(500, fmt.Errorf("token introspection failed: http status not ok: 500 Internal Server Error")),
 			wantServiceErr: true,
 			wantUnauthErr:  false,
 			description:    "500 errors should be returned as ServiceUnavailableErr",
 		},
 		{
 			name:           "502 Bad Gateway returns ServiceUnavailableErr",
-			verifierErr:    fmt.Errorf("token introspection failed: http status not ok: 502 Bad Gateway"),
+			verifierErr:    NewErrorHTTPStatus(502, fmt.Errorf("token introspection failed: http status not ok: 502 Bad Gateway")),
 			wantServiceErr: true,
 			wantUnauthErr:  false,
 			description:    "502 errors should be returned as ServiceUnavailableErr",
 		},
 		{
 			name:           "401 Unauthorized error returns UnauthorizedErr",
-			verifierErr:    fmt.Errorf("token introspection failed: http status not ok: 401 Unauthorized"),
+			verifierErr:    NewErrorHTTPStatus(401, fmt.Errorf("token introspection failed: http status not ok: 401 Unauthorized")),
 			wantServiceErr: false,
 			wantUnauthErr:  true,
 			description:    "401 errors should be returned as UnauthorizedErr",
 		},
 		{
 			name:           "403 Forbidden error returns UnauthorizedErr",
-			verifierErr:    fmt.Errorf("token introspection failed: http status not ok: 403 Forbidden"),
+			verifierErr:    NewErrorHTTPStatus(403, fmt.Errorf("token introspection failed: http status not ok: 403 Forbidden")),
 			wantServiceErr: false,
 			wantUnauthErr:  true,
 			description:    "403 errors should be returned as UnauthorizedErr",
@@ -336,6 +426,69 @@ func TestAuthorizer_CheckAuthorization_ServerError(t *testing.T) {
 			wantUnauthErr:  true,
 			description:    "Errors without 5xx status codes should default to UnauthorizedErr",
 		},
+		{
+			name:           "untyped error mentioning 500 returns UnauthorizedErr",
+			verifierErr:    fmt.Errorf("token introspection failed: dial tcp: invalid port 500 in address"),
+			wantServiceErr: false,
+			wantUnauthErr:  true,
+			description:    "Numbers in the error message must not be interpreted as status codes",
+		},
+		{
+			name:           "timeout returns ServiceUnavailableErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", &url.Error{Op: "Post", URL: "https://zitadel/introspect", Err: &timeoutErr{}}),
+			wantServiceErr: true,
+			wantUnauthErr:  false,
+			description:    "Timeouts should be returned as ServiceUnavailableErr",
+		},
+		{
+			name:           "deadline exceeded returns ServiceUnavailableErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", context.DeadlineExceeded),
+			wantServiceErr: true,
+			wantUnauthErr:  false,
+			description:    "Exceeded deadlines should be returned as ServiceUnavailableErr",
+		},
+		{
+			name:           "dns failure returns ServiceUnavailableErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", &net.DNSError{Err: "no such host", Name: "zitadel", IsNotFound: true}),
+			wantServiceErr: true,
+			wantUnauthErr:  false,
+			description:    "DNS failures should be returned as ServiceUnavailableErr",
+		},
+		{
+			name:           "connection refused returns ServiceUnavailableErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}),
+			wantServiceErr: true,
+			wantUnauthErr:  false,
+			description:    "Refused connections should be returned as ServiceUnavailableErr",
+		},
+		{
+			name:           "canceled context returns UnauthorizedErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", context.Canceled),
+			wantServiceErr: false,
+			wantUnauthErr:  true,
+			description:    "A request canceled by the caller is not a server error",
+		},
+		{
+			name:           "failed tls verification returns UnauthorizedErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", &url.Error{Op: "Post", URL: "https://zitadel/introspect", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}}),
+			wantServiceErr: false,
+			wantUnauthErr:  true,
+			description:    "A misconfigured TLS setup will not resolve by retrying",
+		},
+		{
+			name:           "unsupported protocol scheme returns UnauthorizedErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", &url.Error{Op: "Post", URL: "zitadel/introspect", Err: errors.New("unsupported protocol scheme \"\"")}),
+			wantServiceErr: false,
+			wantUnauthErr:  true,
+			description:    "An invalid URL will not resolve by retrying",
+		},
+		{
+			name:           "non-timeout network error returns UnauthorizedErr",
+			verifierErr:    fmt.Errorf("token introspection failed: %w", &url.Error{Op: "Post", URL: "https://zitadel/introspect", Err: &nonTimeoutErr{}}),
+			wantServiceErr: false,
+			wantUnauthErr:  true,
+			description:    "Only timeouts and connection failures are server errors",
+		},
 	}
 
 	for _, tt := range tests {
@@ -372,7 +525,7 @@ func TestAuthorizer_CheckAuthorization_ServerError(t *testing.T) {
 
 func TestAuthorizer_CheckAuthorization_ServerErrorWithWrappedError(t *testing.T) {
 	// Test that wrapped errors with 5xx status codes are detected
-	baseErr := fmt.Errorf("http status not ok: 503 Service Unavailable")
+	baseErr := NewErrorHTTPStatus(503, fmt.Errorf("http status not ok: 503 Service Unavailable"))
 	wrappedErr := fmt.Errorf("token introspection failed: %w", baseErr)
 
 	authorizer := Authorizer[*testCtx]{
@@ -388,3 +541,44 @@ func TestAuthorizer_CheckAuthorization_ServerErrorWithWrappedError(t *testing.T)
 	var serviceUnavailableErr *ServiceUnavailableErr
 	assert.ErrorAs(t, err, &serviceUnavailableErr, "wrapped 503 error should be detected as ServiceUnavailableErr")
 }
+
+type timeoutErr struct{}
+
+func (e *timeoutErr) Error() string   { return "i/o timeout" }
+func (e *timeoutErr) Timeout() bool   { return true }
+func (e *timeoutErr) Temporary() bool { return true }
+
+type nonTimeoutErr struct{}
+
+func (e *nonTimeoutErr) Error() string   { return "local error" }
+func (e *nonTimeoutErr) Timeout() bool   { return false }
+func (e *nonTimeoutErr) Temporary() bool { return false }
+
+func TestNew_LoggingVerifier(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	verifier := &testLoggingVerifier{}
+	// the verifier is found through wrappers
+	a, err := New(context.Background(), nil,
+		WithCertificateBinding(func(context.Context, *zitadel.Zitadel) (Verifier[*testCtx], error) {
+			return verifier, nil
+		}),
+		WithLogger[*testCtx](logger),
+	)
+	assert.NoError(t, err)
+	assert.Same(t, logger, verifier.logger)
+
+	found, ok := FindVerifier[*testLoggingVerifier](a.verifier)
+	assert.True(t, ok)
+	assert.Same(t, verifier, found)
+	_, ok = FindVerifier[*testVerifier[*testCtx]](a.verifier)
+	assert.False(t, ok)
+}
+
+type testLoggingVerifier struct {
+	testVerifier[*testCtx]
+	logger *slog.Logger
+}
+
+func (t *testLoggingVerifier) SetLogger(logger *slog.Logger) {
+	t.logger = logger
+}
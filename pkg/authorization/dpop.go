@@ -0,0 +1,333 @@
+package authorization
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+)
+
+const (
+	// SchemeDPoP is the authorization scheme of DPoP (RFC 9449) sender-constrained access tokens.
+	SchemeDPoP = "DPoP"
+	// HeaderDPoP is the name of the header (or gRPC metadata) carrying the DPoP proof.
+	HeaderDPoP = "dpop"
+
+	DefaultDPoPMaxAge = time.Minute
+	DefaultDPoPLeeway = 10 * time.Second
+
+	dpopProofType = "dpop+jwt"
+
+	// dpopReplaySweepInterval is the minimum interval in which the [MemoryDPoPReplayCache] removes expired entries.
+	dpopReplaySweepInterval = time.Minute
+)
+
+var (
+	ErrInvalidDPoPProof    = errors.New("invalid DPoP proof")
+	ErrDPoPProofReplayed   = errors.New("DPoP proof was already used")
+	ErrDPoPBindingMismatch = errors.New("access token is not bound to the DPoP proof key")
+	ErrDPoPRequired        = errors.New("DPoP scheme is required")
+)
+
+// DefaultDPoPSigningAlgorithms are the asymmetric algorithms accepted for the signature of DPoP proofs.
+var DefaultDPoPSigningAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// DPoPCtx is an optional extension of [Ctx] providing the key binding of a DPoP sender-constrained access token.
+// It is required if DPoP is enabled on the [Authorizer] (see [WithDPoP]).
+type DPoPCtx interface {
+	// JWKThumbprint returns the `cnf.jkt` claim (the base64url encoded SHA-256 JWK thumbprint of the proof key)
+	// or an empty string if the token is not sender-constrained.
+	JWKThumbprint() string
+}
+
+// DPoPReplayCache keeps track of the used DPoP proofs (by their `jti`) to prevent their replay.
+type DPoPReplayCache interface {
+	// Use marks the proof as used until the expiry and returns false if it was already used.
+	Use(ctx context.Context, jti string, expiry time.Time) (bool, error)
+}
+
+// MemoryDPoPReplayCache is an in-memory [DPoPReplayCache]. Expired entries are ignored and removed at most once per minute.
+// It is the default of [WithDPoP], but is not shared across multiple instances of the application.
+type MemoryDPoPReplayCache struct {
+	mutex     sync.Mutex
+	entries   map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewMemoryDPoPReplayCache() *MemoryDPoPReplayCache {
+	return &MemoryDPoPReplayCache{
+		entries: make(map[string]time.Time),
+	}
+}
+
+// Use implements [DPoPReplayCache].
+func (c *MemoryDPoPReplayCache) Use(_ context.Context, jti string, expiry time.Time) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	if now.After(c.nextSweep) {
+		c.sweep(now)
+	}
+	if until, ok := c.entries[jti]; ok && !now.After(until) {
+		return false, nil
+	}
+	c.entries[jti] = expiry
+	return true, nil
+}
+
+func (c *MemoryDPoPReplayCache) sweep(now time.Time) {
+	for id, until := range c.entries {
+		if now.After(until) {
+			delete(c.entries, id)
+		}
+	}
+	c.nextSweep = now.Add(dpopReplaySweepInterval)
+}
+
+// DPoPOption allows customization of the DPoP proof validation such as the replay cache.
+type DPoPOption func(*dpopConfig)
+
+// WithDPoPReplayCache allows a [DPoPReplayCache] other than the [MemoryDPoPReplayCache],
+// e.g. a shared cache if the application runs on multiple instances.
+func WithDPoPReplayCache(cache DPoPReplayCache) DPoPOption {
+	return func(c *dpopConfig) {
+		c.replayCache = cache
+	}
+}
+
+// WithDPoPMaxAge sets the maximum age of a proof (`iat` claim). Default is [DefaultDPoPMaxAge].
+func WithDPoPMaxAge(maxAge time.Duration) DPoPOption {
+	return func(c *dpopConfig) {
+		c.maxAge = maxAge
+	}
+}
+
+// WithDPoPLeeway sets the tolerated clock skew for proofs issued in the future. Default is [DefaultDPoPLeeway].
+func WithDPoPLeeway(leeway time.Duration) DPoPOption {
+	return func(c *dpopConfig) {
+		c.leeway = leeway
+	}
+}
+
+// WithDPoPSigningAlgorithms restricts the accepted signature algorithms of proofs.
+// Default is [DefaultDPoPSigningAlgorithms].
+func WithDPoPSigningAlgorithms(algorithms ...jose.SignatureAlgorithm) DPoPOption {
+	return func(c *dpopConfig) {
+		c.algorithms = algorithms
+	}
+}
+
+// WithDPoPOrigin sets the external origin (e.g. `https://api.example.com`) used to compare the `htu` claim,
+// e.g. if TLS is terminated by a proxy. By default, the origin is derived from the request.
+func WithDPoPOrigin(origin string) DPoPOption {
+	return func(c *dpopConfig) {
+		c.origin = strings.TrimSuffix(origin, "/")
+	}
+}
+
+// WithDPoPRequired rejects access tokens presented with the `Bearer` scheme with an [ErrDPoPRequired].
+// By default, bearer tokens are accepted unless they are sender-constrained (have a `cnf.jkt` claim).
+func WithDPoPRequired() DPoPOption {
+	return func(c *dpopConfig) {
+		c.required = true
+	}
+}
+
+// WithDPoP enables the `DPoP` authorization scheme (RFC 9449) on the [Authorizer].
+// The proof is read from the `DPoP` header of the request (see [WithRequest]) or the [RequestInfo] (e.g. the `dpop`
+// metadata of a gRPC call provided by the gRPC interceptor) and validated against the method and URL of the request and the access token. The key of the proof must match the
+// `cnf.jkt` claim of the access token (see [DPoPCtx]). A failed validation returns an [ErrInvalidDPoPProof],
+// [ErrDPoPProofReplayed] or [ErrDPoPBindingMismatch] as [UnauthorizedErr].
+func WithDPoP[T Ctx](options ...DPoPOption) Option[T] {
+	return func(a *Authorizer[T]) {
+		config := &dpopConfig{
+			replayCache: NewMemoryDPoPReplayCache(),
+			maxAge:      DefaultDPoPMaxAge,
+			leeway:      DefaultDPoPLeeway,
+			algorithms:  DefaultDPoPSigningAlgorithms,
+		}
+		for _, option := range options {
+			option(config)
+		}
+		a.dpop = config
+	}
+}
+
+type dpopConfig struct {
+	replayCache DPoPReplayCache
+	maxAge      time.Duration
+	leeway      time.Duration
+	algorithms  []jose.SignatureAlgorithm
+	origin      string
+	required    bool
+}
+
+type dpopClaims struct {
+	JWTID           string `json:"jti"`
+	Method          string `json:"htm"`
+	URI             string `json:"htu"`
+	IssuedAt        int64  `json:"iat"`
+	AccessTokenHash string `json:"ath"`
+}
+
+// verifiedDPoPProof is a valid DPoP proof, which is not yet marked as used.
+type verifiedDPoPProof struct {
+	jkt    string
+	jwtID  string
+	expiry time.Time
+}
+
+// verifyProof validates the DPoP proof of the request for the access token.
+// The proof is not marked as used, so a request with an invalid access token does not consume it (see [dpopConfig.useProof]).
+func (c *dpopConfig) verifyProof(ctx context.Context, accessToken string) (*verifiedDPoPProof, error) {
+	proofs, method, uri := dpopRequest(ctx, c.origin)
+	if len(proofs) != 1 {
+		return nil, fmt.Errorf("%w: exactly one proof is required, got %d", ErrInvalidDPoPProof, len(proofs))
+	}
+	jws, err := jose.ParseSignedCompact(proofs[0], c.algorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDPoPProof, err)
+	}
+	header := jws.Signatures[0].Protected
+	if typ, _ := header.ExtraHeaders[jose.HeaderType].(string); !strings.EqualFold(typ, dpopProofType) {
+		return nil, fmt.Errorf("%w: invalid type `%s`", ErrInvalidDPoPProof, typ)
+	}
+	key := header.JSONWebKey
+	if key == nil || !key.Valid() || !key.IsPublic() {
+		return nil, fmt.Errorf("%w: missing or invalid public key", ErrInvalidDPoPProof)
+	}
+	payload, err := jws.Verify(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDPoPProof, err)
+	}
+	var claims dpopClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDPoPProof, err)
+	}
+	if claims.JWTID == "" {
+		return nil, fmt.Errorf("%w: missing jti", ErrInvalidDPoPProof)
+	}
+	if claims.Method != method {
+		return nil, fmt.Errorf("%w: htm `%s` does not match `%s`", ErrInvalidDPoPProof, claims.Method, method)
+	}
+	if !matchURI(claims.URI, uri) {
+		return nil, fmt.Errorf("%w: htu `%s` does not match `%s`", ErrInvalidDPoPProof, claims.URI, uri)
+	}
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	now := time.Now()
+	if claims.IssuedAt == 0 || issuedAt.Before(now.Add(-c.maxAge)) || issuedAt.After(now.Add(c.leeway)) {
+		return nil, fmt.Errorf("%w: iat is missing or outside the accepted window", ErrInvalidDPoPProof)
+	}
+	hash := sha256.Sum256([]byte(accessToken))
+	if claims.AccessTokenHash != base64.RawURLEncoding.EncodeToString(hash[:]) {
+		return nil, fmt.Errorf("%w: ath does not match the access token", ErrInvalidDPoPProof)
+	}
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDPoPProof, err)
+	}
+	return &verifiedDPoPProof{
+		jkt:    base64.RawURLEncoding.EncodeToString(thumbprint),
+		jwtID:  claims.JWTID,
+		expiry: issuedAt.Add(c.maxAge + c.leeway),
+	}, nil
+}
+
+// useProof marks the proof as used in the [DPoPReplayCache] and returns an [ErrDPoPProofReplayed] if it was already used.
+func (c *dpopConfig) useProof(ctx context.Context, proof *verifiedDPoPProof) error {
+	unused, err := c.replayCache.Use(ctx, proof.jkt+":"+proof.jwtID, proof.expiry)
+	if err != nil {
+		return err
+	}
+	if !unused {
+		return fmt.Errorf("%w: %w", ErrInvalidDPoPProof, ErrDPoPProofReplayed)
+	}
+	return nil
+}
+
+// checkBinding verifies that a sender-constrained token is presented with a proof of its key
+// and that a token presented with the DPoP scheme is bound to the key of the proof.
+func (c *dpopConfig) checkBinding(authCtx Ctx, scheme string, proof *verifiedDPoPProof) error {
+	dpopCtx, ok := authCtx.(DPoPCtx)
+	if !ok {
+		if scheme == SchemeDPoP {
+			return ErrUnsupportedContext
+		}
+		return nil
+	}
+	bound := dpopCtx.JWKThumbprint()
+	switch {
+	case scheme != SchemeDPoP && bound != "":
+		return fmt.Errorf("%w: sender-constrained token must be presented with the DPoP scheme", ErrDPoPBindingMismatch)
+	case scheme == SchemeDPoP && bound != proof.jkt:
+		return ErrDPoPBindingMismatch
+	}
+	return nil
+}
+
+// splitAuthorizationHeader returns the scheme and access token of a `Bearer` or `DPoP` authorization header.
+func splitAuthorizationHeader(header string) (scheme, token string, err error) {
+	header = strings.TrimSpace(header)
+	for _, scheme := range []string{oidc.BearerToken, SchemeDPoP} {
+		token, ok := strings.CutPrefix(header, scheme+" ")
+		if ok && token != "" {
+			return scheme, strings.TrimSpace(token), nil
+		}
+	}
+	return "", "", ErrMissingToken
+}
+
+// dpopRequest returns the DPoP proofs, the method and the URI (without query) of the [*http.Request]
+// provided by [WithRequest] or of the [RequestInfo] provided by [WithRequestInfo] (e.g. of a gRPC call).
+func dpopRequest(ctx context.Context, origin string) (proofs []string, method, uri string) {
+	if req, ok := Request(ctx).(*http.Request); ok {
+		if origin == "" {
+			scheme := "http"
+			if req.TLS != nil {
+				scheme = "https"
+			}
+			origin = scheme + "://" + req.Host
+		}
+		return req.Header.Values(HeaderDPoP), req.Method, origin + req.URL.Path
+	}
+	info := RequestInfoFromContext(ctx)
+	if info == nil {
+		return nil, "", ""
+	}
+	if origin == "" {
+		origin = info.Scheme + "://" + info.Host
+	}
+	return info.DPoPProofs, info.Method, origin + info.Path
+}
+
+// matchURI compares the `htu` claim with the URI of the request, ignoring query and fragment
+// as well as the case of scheme and host.
+func matchURI(htu, uri string) bool {
+	claimed, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+	expected, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(claimed.Scheme, expected.Scheme) &&
+		strings.EqualFold(claimed.Host, expected.Host) &&
+		claimed.Path == expected.Path
+}
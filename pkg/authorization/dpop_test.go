@@ -0,0 +1,269 @@
+package authorization
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dpopAccessToken = "access-token"
+
+func TestAuthorizer_CheckAuthorization_DPoP(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jkt := testThumbprint(t, key)
+
+	replayed := testDPoPProof(t, key, dpopProof{})
+	tests := []struct {
+		name    string
+		options []DPoPOption
+		header  string
+		proofs  []string
+		jkt     string
+		wantErr error
+	}{
+		{
+			name:   "valid proof",
+			header: "DPoP " + dpopAccessToken,
+			proofs: []string{replayed},
+			jkt:    jkt,
+		},
+		{
+			name:    "replayed proof",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{replayed},
+			jkt:     jkt,
+			wantErr: ErrDPoPProofReplayed,
+		},
+		{
+			name:    "missing proof",
+			header:  "DPoP " + dpopAccessToken,
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "multiple proofs",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{}), testDPoPProof(t, key, dpopProof{})},
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "invalid type",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{typ: "JWT"})},
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "wrong method",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{htm: "POST"})},
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "wrong uri",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{htu: "https://api.example.com/other"})},
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "proof too old",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{iat: time.Now().Add(-time.Hour)})},
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "proof of other access token",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{accessToken: "other"})},
+			jkt:     jkt,
+			wantErr: ErrInvalidDPoPProof,
+		},
+		{
+			name:    "token bound to other key",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, otherKey, dpopProof{})},
+			jkt:     jkt,
+			wantErr: ErrDPoPBindingMismatch,
+		},
+		{
+			name:    "unbound token with proof",
+			header:  "DPoP " + dpopAccessToken,
+			proofs:  []string{testDPoPProof(t, key, dpopProof{})},
+			wantErr: ErrDPoPBindingMismatch,
+		},
+		{
+			name:    "bound token as bearer",
+			header:  "Bearer " + dpopAccessToken,
+			jkt:     jkt,
+			wantErr: ErrDPoPBindingMismatch,
+		},
+		{
+			name:   "unbound token as bearer",
+			header: "Bearer " + dpopAccessToken,
+		},
+		{
+			name:    "bearer with dpop required",
+			options: []DPoPOption{WithDPoPRequired()},
+			header:  "Bearer " + dpopAccessToken,
+			wantErr: ErrDPoPRequired,
+		},
+	}
+	replayCache := NewMemoryDPoPReplayCache()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAuthorizer(&testCtx{isAuthorized: true, jkt: tt.jkt})
+			WithDPoP[*testCtx](append([]DPoPOption{WithDPoPReplayCache(replayCache)}, tt.options...)...)(a)
+
+			req := httptest.NewRequest("GET", "https://api.example.com/resource?query=value", nil)
+			for _, proof := range tt.proofs {
+				req.Header.Add(HeaderDPoP, proof)
+			}
+			_, err := a.CheckAuthorization(WithRequest(context.Background(), req), tt.header)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, NewErrorUnauthorized(tt.wantErr))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer "+dpopAccessToken, a.verifier.(*testVerifier[*testCtx]).token)
+		})
+	}
+}
+
+func TestAuthorizer_CheckAuthorization_DPoPDisabled(t *testing.T) {
+	a := newTestAuthorizer(&testCtx{isAuthorized: true})
+	_, err := a.CheckAuthorization(context.Background(), "DPoP "+dpopAccessToken)
+	assert.ErrorIs(t, err, NewErrorUnauthorized(ErrMissingToken))
+}
+
+func TestAuthorizer_CheckAuthorization_DPoPRequestInfo(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	a := newTestAuthorizer(&testCtx{isAuthorized: true, jkt: testThumbprint(t, key)})
+	WithDPoP[*testCtx]()(a)
+
+	proof := testDPoPProof(t, key, dpopProof{htm: "POST", htu: "https://api.example.com/pkg.Service/Get"})
+	ctx := WithRequestInfo(context.Background(), &RequestInfo{
+		Scheme:     "https",
+		Host:       "api.example.com",
+		Method:     "POST",
+		Path:       "/pkg.Service/Get",
+		DPoPProofs: []string{proof},
+	})
+	_, err = a.CheckAuthorization(ctx, "DPoP "+dpopAccessToken)
+	assert.NoError(t, err)
+}
+
+// TestAuthorizer_CheckAuthorization_DPoPInvalidToken verifies that a proof is not consumed by a request with an invalid token.
+func TestAuthorizer_CheckAuthorization_DPoPInvalidToken(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	authCtx := &testCtx{jkt: testThumbprint(t, key)}
+	a := newTestAuthorizer(authCtx)
+	WithDPoP[*testCtx]()(a)
+
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	req.Header.Add(HeaderDPoP, testDPoPProof(t, key, dpopProof{}))
+	ctx := WithRequest(context.Background(), req)
+	_, err = a.CheckAuthorization(ctx, "DPoP "+dpopAccessToken)
+	require.ErrorIs(t, err, NewErrorUnauthorized(nil))
+	assert.NotErrorIs(t, err, ErrDPoPProofReplayed)
+
+	authCtx.isAuthorized = true
+	_, err = a.CheckAuthorization(ctx, "DPoP "+dpopAccessToken)
+	require.NoError(t, err)
+	_, err = a.CheckAuthorization(ctx, "DPoP "+dpopAccessToken)
+	assert.ErrorIs(t, err, ErrDPoPProofReplayed)
+}
+
+func TestMemoryDPoPReplayCache_Use(t *testing.T) {
+	cache := NewMemoryDPoPReplayCache()
+	unused, err := cache.Use(context.Background(), "expired", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	assert.True(t, unused)
+	unused, err = cache.Use(context.Background(), "valid", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, unused)
+
+	// expired entries are ignored before they are removed
+	unused, err = cache.Use(context.Background(), "expired", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, unused)
+	unused, err = cache.Use(context.Background(), "valid", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, unused)
+}
+
+type dpopProof struct {
+	typ         string
+	htm         string
+	htu         string
+	iat         time.Time
+	accessToken string
+}
+
+func testDPoPProof(t *testing.T, key *ecdsa.PrivateKey, proof dpopProof) string {
+	t.Helper()
+	if proof.typ == "" {
+		proof.typ = "dpop+jwt"
+	}
+	if proof.htm == "" {
+		proof.htm = "GET"
+	}
+	if proof.htu == "" {
+		proof.htu = "https://api.example.com/resource"
+	}
+	if proof.iat.IsZero() {
+		proof.iat = time.Now()
+	}
+	if proof.accessToken == "" {
+		proof.accessToken = dpopAccessToken
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: key},
+		(&jose.SignerOptions{EmbedJWK: true}).WithType(jose.ContentType(proof.typ)),
+	)
+	require.NoError(t, err)
+	jti := make([]byte, 16)
+	_, err = rand.Read(jti)
+	require.NoError(t, err)
+	ath := sha256.Sum256([]byte(proof.accessToken))
+	payload, err := json.Marshal(map[string]any{
+		"jti": base64.RawURLEncoding.EncodeToString(jti),
+		"htm": proof.htm,
+		"htu": proof.htu,
+		"iat": proof.iat.Unix(),
+		"ath": base64.RawURLEncoding.EncodeToString(ath[:]),
+	})
+	require.NoError(t, err)
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+	compact, err := jws.CompactSerialize()
+	require.NoError(t, err)
+	return compact
+}
+
+func testThumbprint(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	thumbprint, err := (&jose.JSONWebKey{Key: key.Public()}).Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(thumbprint)
+}
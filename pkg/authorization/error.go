@@ -2,6 +2,7 @@ package authorization
 
 import (
 	"errors"
+	"strconv"
 )
 
 // UnauthorizedErr is used to provide the information to the caller, that the provided authorization
@@ -72,6 +73,13 @@ func (e *PermissionDeniedErr) Unwrap() error {
 	return e.err
 }
 
+// FailedChecks returns the failed checks (branches) of composed expressions such as [AnyOf] or [AllOf]
+// and of named checks such as [WithPredicate].
+// It returns nil if the permission was denied by a check without branch information (e.g. [WithRole]).
+func (e *PermissionDeniedErr) FailedChecks() []*CheckErr {
+	return failedChecks(e.err)
+}
+
 // ServiceUnavailableErr is used to indicate that the authorization service is temporarily unavailable (5xx errors).
 // This allows downstream code to distinguish between client errors (4xx) and server errors (5xx).
 type ServiceUnavailableErr struct {
@@ -105,3 +113,42 @@ func (e *ServiceUnavailableErr) Is(target error) bool {
 func (e *ServiceUnavailableErr) Unwrap() error {
 	return e.err
 }
+
+// HTTPStatusErr is returned by [Verifier] implementations if a request to ZITADEL (e.g. the introspection endpoint)
+// was answered with a non-successful HTTP status code. It allows the [Authorizer] to distinguish client errors (4xx)
+// from server errors (5xx) without inspecting the error message.
+type HTTPStatusErr struct {
+	StatusCode int
+	err        error
+}
+
+func NewErrorHTTPStatus(statusCode int, err error) *HTTPStatusErr {
+	return &HTTPStatusErr{
+		StatusCode: statusCode,
+		err:        err,
+	}
+}
+
+func (e *HTTPStatusErr) Error() string {
+	if e.err == nil {
+		return "http status " + strconv.Itoa(e.StatusCode)
+	}
+	return e.err.Error()
+}
+
+func (e *HTTPStatusErr) Is(target error) bool {
+	t, ok := target.(*HTTPStatusErr)
+	if !ok {
+		return false
+	}
+	return t.StatusCode == 0 || t.StatusCode == e.StatusCode
+}
+
+func (e *HTTPStatusErr) Unwrap() error {
+	return e.err
+}
+
+// IsServerError returns true for 5xx status codes.
+func (e *HTTPStatusErr) IsServerError() bool {
+	return e.StatusCode >= 500 && e.StatusCode < 600
+}
@@ -0,0 +1,239 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ErrMissingOrganization = errors.New("missing required organization")
+	ErrUserMismatch        = errors.New("user does not match")
+	ErrCheckFailed         = errors.New("check failed")
+	ErrNegatedCheck        = errors.New("negated check succeeded")
+	ErrNoBranchMatched     = errors.New("none of the checks succeeded")
+	ErrEvaluationFailed    = errors.New("check evaluation failed")
+)
+
+// CheckErr describes a failed check of a composed expression (see [AnyOf], [AllOf] and [Not]) or a named check
+// (see [WithPredicate] and [WithRequestCheck]).
+// Branch is the path of the failed check inside the expression, e.g. `anyOf[1].allOf[0]`,
+// which ends with the name of a named check, e.g. `anyOf[1].is owner`.
+type CheckErr struct {
+	Branch string
+	err    error
+}
+
+// NewCheckErr creates a [CheckErr] for a named check, e.g. of a custom [CheckOption],
+// so the failed check is reported by [PermissionDeniedErr.FailedChecks].
+func NewCheckErr(name string, err error) *CheckErr {
+	return &CheckErr{Branch: name, err: err}
+}
+
+func (e *CheckErr) Error() string {
+	if e.Branch == "" {
+		return e.err.Error()
+	}
+	return e.Branch + ": " + e.err.Error()
+}
+
+func (e *CheckErr) Unwrap() error {
+	return e.err
+}
+
+// AnyOf requires at least one of the provided options to succeed (logical OR).
+// Each option is evaluated as its own branch, so multiple checks of a single option are still combined with AND.
+// If no branch succeeds, an [ErrNoBranchMatched] is returned together with the failure of every branch.
+func AnyOf(options ...CheckOption) CheckOption {
+	return func(checks *Check[Ctx]) {
+		branches := buildBranches(checks.ctx, options)
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			errs := make([]error, 0, len(branches)+1)
+			errs = append(errs, ErrNoBranchMatched)
+			for i, branch := range branches {
+				err := branch(authCtx)
+				if err == nil {
+					return nil
+				}
+				errs = append(errs, withBranch("anyOf["+strconv.Itoa(i)+"]", err))
+			}
+			return errors.Join(errs...)
+		})
+	}
+}
+
+// AllOf requires all of the provided options to succeed (logical AND).
+// In contrast to passing the options directly, a failure will report the failed branch.
+func AllOf(options ...CheckOption) CheckOption {
+	return func(checks *Check[Ctx]) {
+		branches := buildBranches(checks.ctx, options)
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			for i, branch := range branches {
+				if err := branch(authCtx); err != nil {
+					return withBranch("allOf["+strconv.Itoa(i)+"]", err)
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// Not inverts the provided option: it succeeds if the option fails and vice versa.
+// If the option succeeds, an [ErrNegatedCheck] is returned.
+// Only denials are inverted: if the option could not be evaluated, e.g. because of an [ErrUnsupportedContext],
+// an [ErrUnsupportedRequest] or an [ErrEvaluationFailed], the error is returned unchanged.
+func Not(option CheckOption) CheckOption {
+	return func(checks *Check[Ctx]) {
+		branch := buildBranches(checks.ctx, []CheckOption{option})[0]
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if err := branch(authCtx); err != nil {
+				if isEvaluationErr(err) {
+					return err
+				}
+				return nil
+			}
+			return &CheckErr{Branch: "not", err: ErrNegatedCheck}
+		})
+	}
+}
+
+// isEvaluationErr reports whether the error means that a check could not be evaluated, rather than that it denied the access.
+func isEvaluationErr(err error) bool {
+	return errors.Is(err, ErrUnsupportedContext) ||
+		errors.Is(err, ErrUnsupportedRequest) ||
+		errors.Is(err, ErrEvaluationFailed)
+}
+
+// WithRoleInOrganization requires the authorized user to be granted the provided role in the specified organization.
+// If the role is not granted to the user, an [ErrMissingRole] is returned.
+func WithRoleInOrganization(role, organizationID string) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if authCtx.IsGrantedRoleInOrganization(role, organizationID) {
+				return nil
+			}
+			return fmt.Errorf("%w: `%s` in organization `%s`", ErrMissingRole, role, organizationID)
+		})
+	}
+}
+
+// WithRoleInProject requires the authorized user to be granted the provided role in the specified project.
+// If organizationID is empty, the role may be granted in any organization.
+// If the role is not granted to the user, an [ErrMissingRole] is returned.
+func WithRoleInProject(projectID, role, organizationID string) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if authCtx.IsGrantedRoleInProject(projectID, role, organizationID) {
+				return nil
+			}
+			if organizationID == "" {
+				return fmt.Errorf("%w: `%s` in project `%s`", ErrMissingRole, role, projectID)
+			}
+			return fmt.Errorf("%w: `%s` in project `%s` and organization `%s`", ErrMissingRole, role, projectID, organizationID)
+		})
+	}
+}
+
+// WithOrganization requires the authorized user to belong to the specified organization.
+// If the user belongs to another organization, an [ErrMissingOrganization] is returned.
+func WithOrganization(organizationID string) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if authCtx.OrganizationID() == organizationID {
+				return nil
+			}
+			return fmt.Errorf("%w: `%s`", ErrMissingOrganization, organizationID)
+		})
+	}
+}
+
+// WithUserID requires the authorized user to be the user with the specified id.
+// If the id does not match, an [ErrUserMismatch] is returned.
+func WithUserID(userID string) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if authCtx.UserID() == userID {
+				return nil
+			}
+			return fmt.Errorf("%w: `%s`", ErrUserMismatch, userID)
+		})
+	}
+}
+
+// WithPredicate allows a custom requirement on the authorization context.
+// The name is used to identify the predicate in case of a failure, which returns a [CheckErr] wrapping an [ErrCheckFailed].
+func WithPredicate(name string, predicate func(authCtx Ctx) bool) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if predicate(authCtx) {
+				return nil
+			}
+			return NewCheckErr(name, ErrCheckFailed)
+		})
+	}
+}
+
+// buildBranches evaluates every option into its own [Check] (with the context of the request) and returns
+// a function per option, which succeeds if all checks of the option succeed.
+func buildBranches(ctx context.Context, options []CheckOption) []func(authCtx Ctx) error {
+	branches := make([]func(authCtx Ctx) error, len(options))
+	for i, option := range options {
+		branch := &Check[Ctx]{ctx: ctx}
+		option(branch)
+		branches[i] = func(authCtx Ctx) error {
+			for _, c := range branch.Checks {
+				if err := c(authCtx); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return branches
+}
+
+// withBranch prefixes the branch path of all [CheckErr] contained in err with the provided prefix.
+// Errors without branch information are wrapped into a new [CheckErr].
+func withBranch(prefix string, err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		errs := e.Unwrap()
+		prefixed := make([]error, len(errs))
+		for i, err := range errs {
+			if err == ErrNoBranchMatched { //nolint:errorlint // only the plain sentinel is kept as is
+				prefixed[i] = err
+				continue
+			}
+			prefixed[i] = withBranch(prefix, err)
+		}
+		return errors.Join(prefixed...)
+	case *CheckErr:
+		return &CheckErr{Branch: prefix + "." + e.Branch, err: e.err}
+	default:
+		return &CheckErr{Branch: prefix, err: err}
+	}
+}
+
+// failedChecks collects all [CheckErr] of the error tree, which are not wrapping another [CheckErr].
+func failedChecks(err error) []*CheckErr {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *CheckErr:
+		if nested := failedChecks(e.err); len(nested) > 0 {
+			return nested
+		}
+		return []*CheckErr{e}
+	case interface{ Unwrap() []error }:
+		var checkErrs []*CheckErr
+		for _, err := range e.Unwrap() {
+			checkErrs = append(checkErrs, failedChecks(err)...)
+		}
+		return checkErrs
+	case interface{ Unwrap() error }:
+		return failedChecks(e.Unwrap())
+	default:
+		return nil
+	}
+}
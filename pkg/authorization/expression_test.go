@@ -0,0 +1,170 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckExpressions(t *testing.T) {
+	authCtx := &testCtx{
+		isAuthorized:                true,
+		organizationID:              "org",
+		userID:                      "user",
+		isGrantedRole:               false,
+		isGrantedRoleInOrganization: true,
+		isGrantedRoleInProject:      false,
+	}
+	tests := []struct {
+		name         string
+		options      []CheckOption
+		wantErr      error
+		wantBranches []string
+	}{
+		{
+			name:    "organization matches",
+			options: []CheckOption{WithOrganization("org")},
+		},
+		{
+			name:    "organization does not match",
+			options: []CheckOption{WithOrganization("other")},
+			wantErr: ErrMissingOrganization,
+		},
+		{
+			name:    "user matches",
+			options: []CheckOption{WithUserID("user")},
+		},
+		{
+			name:    "user does not match",
+			options: []CheckOption{WithUserID("other")},
+			wantErr: ErrUserMismatch,
+		},
+		{
+			name:    "role in organization",
+			options: []CheckOption{WithRoleInOrganization("editor", "org")},
+		},
+		{
+			name:    "missing role in project",
+			options: []CheckOption{WithRoleInProject("project", "editor", "")},
+			wantErr: ErrMissingRole,
+		},
+		{
+			name: "predicate fails",
+			options: []CheckOption{WithPredicate("always false", func(Ctx) bool {
+				return false
+			})},
+			wantErr:      ErrCheckFailed,
+			wantBranches: []string{"always false"},
+		},
+		{
+			name: "anyOf, second branch succeeds",
+			options: []CheckOption{AnyOf(
+				WithRole("admin"),
+				AllOf(WithRoleInOrganization("editor", "org"), WithOrganization("org")),
+			)},
+		},
+		{
+			name: "anyOf, no branch succeeds",
+			options: []CheckOption{AnyOf(
+				WithRole("admin"),
+				AllOf(WithRoleInOrganization("editor", "org"), WithOrganization("other")),
+			)},
+			wantErr:      ErrNoBranchMatched,
+			wantBranches: []string{"anyOf[0]", "anyOf[1].allOf[1]"},
+		},
+		{
+			name: "anyOf with named check",
+			options: []CheckOption{AnyOf(
+				WithPredicate("is owner", func(Ctx) bool { return false }),
+				WithRole("admin"),
+			)},
+			wantErr:      ErrCheckFailed,
+			wantBranches: []string{"anyOf[0].is owner", "anyOf[1]"},
+		},
+		{
+			name: "nested anyOf inside allOf",
+			options: []CheckOption{AllOf(
+				WithUserID("user"),
+				AnyOf(WithRole("admin"), WithOrganization("other")),
+			)},
+			wantErr:      ErrMissingOrganization,
+			wantBranches: []string{"allOf[1].anyOf[0]", "allOf[1].anyOf[1]"},
+		},
+		{
+			name:    "not, inner check fails",
+			options: []CheckOption{Not(WithRole("admin"))},
+		},
+		{
+			name:         "not, inner check succeeds",
+			options:      []CheckOption{Not(WithUserID("user"))},
+			wantErr:      ErrNegatedCheck,
+			wantBranches: []string{"not"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestAuthorizer(authCtx).CheckAuthorization(context.Background(), "Bearer token", tt.options...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, NewErrorPermissionDenied(tt.wantErr))
+			var permissionDenied *PermissionDeniedErr
+			require.True(t, errors.As(err, &permissionDenied))
+			branches := make([]string, 0)
+			for _, checkErr := range permissionDenied.FailedChecks() {
+				branches = append(branches, checkErr.Branch)
+			}
+			if tt.wantBranches == nil {
+				assert.Empty(t, branches)
+				return
+			}
+			assert.Equal(t, tt.wantBranches, branches)
+		})
+	}
+}
+
+func TestNot_EvaluationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		authCtx Ctx
+		option  CheckOption
+		wantErr error
+	}{
+		{
+			name:    "unsupported context",
+			authCtx: newTestBasicCtx(),
+			option:  WithImpersonation(),
+			wantErr: ErrUnsupportedContext,
+		},
+		{
+			name:    "unsupported request",
+			authCtx: &testCtx{isAuthorized: true},
+			option: WithRequestCheck("request", func(context.Context, Ctx, string) error {
+				return nil
+			}),
+			wantErr: ErrUnsupportedRequest,
+		},
+		{
+			name:    "nested evaluation failure",
+			authCtx: &testCtx{isAuthorized: true},
+			option: AnyOf(func(checks *Check[Ctx]) {
+				checks.Checks = append(checks.Checks, func(Ctx) error {
+					return fmt.Errorf("%w: missing claim", ErrEvaluationFailed)
+				})
+			}),
+			wantErr: ErrEvaluationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestAuthorizer(tt.authCtx).CheckAuthorization(context.Background(), "Bearer token", Not(tt.option))
+			assert.ErrorIs(t, err, NewErrorPermissionDenied(tt.wantErr))
+			assert.NotErrorIs(t, err, ErrNegatedCheck)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package authorization
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+var (
+	ErrUnknownInstance = errors.New("unknown ZITADEL instance")
+)
+
+// InstanceCtx is an optional extension of [Ctx] providing the ZITADEL instance, which issued the token.
+// It is set by the [MultiAuthorizer].
+type InstanceCtx interface {
+	SetInstance(instance *zitadel.Zitadel)
+	Instance() *zitadel.Zitadel
+}
+
+// Instance returns the ZITADEL instance, which issued the token of the authorized user (see [InstanceCtx]).
+// In case of an unauthorized caller or a context not implementing [InstanceCtx], it is nil.
+// Since the context is only set after the authorization, checks need to use the [InstanceCtx] they are called with.
+func Instance(ctx context.Context) *zitadel.Zitadel {
+	instanceCtx, ok := Context[Ctx](ctx).(InstanceCtx)
+	if !ok {
+		return nil
+	}
+	return instanceCtx.Instance()
+}
+
+// InstanceResolver resolves the ZITADEL instance for the (not yet verified) issuer of the token and the host of the request.
+// The issuer is only available for JWT, the host only if the request is provided (see [WithRequest]).
+// Since the issuer is not verified yet, only trusted instances (e.g. of an allowlist or registry) must be returned.
+// If no instance matches, an [ErrUnknownInstance] is expected to be returned.
+type InstanceResolver func(ctx context.Context, issuer, host string) (*zitadel.Zitadel, error)
+
+// AllowInstances creates an [InstanceResolver] for the provided instances.
+// The host (without port) is matched with the domain of the instance, the issuer with the origin (see [zitadel.Zitadel.Origin]).
+// If the host matches an instance, the token must be issued by this instance, so that a token of one instance
+// is never accepted on the domain of another. Only if the host does not match any instance, the instance is resolved by the issuer.
+func AllowInstances(instances ...*zitadel.Zitadel) InstanceResolver {
+	byIssuer := make(map[string]*zitadel.Zitadel, len(instances))
+	byDomain := make(map[string]*zitadel.Zitadel, len(instances))
+	for _, instance := range instances {
+		byIssuer[instance.Origin()] = instance
+		byDomain[instance.Domain()] = instance
+	}
+	return func(_ context.Context, issuer, host string) (*zitadel.Zitadel, error) {
+		domain := host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			domain = hostname
+		}
+		if instance, ok := byDomain[domain]; ok {
+			if issuer != "" && issuer != instance.Origin() {
+				return nil, fmt.Errorf("%w: issuer `%s` does not match the instance of host `%s`", ErrUnknownInstance, issuer, host)
+			}
+			return instance, nil
+		}
+		if instance, ok := byIssuer[issuer]; ok {
+			return instance, nil
+		}
+		return nil, fmt.Errorf("%w: issuer `%s`, host `%s`", ErrUnknownInstance, issuer, host)
+	}
+}
+
+// MultiAuthorizer provides the functionality of the [Authorizer] for tokens of multiple ZITADEL instances
+// (e.g. per customer on a custom domain). The instance is resolved per request by an [InstanceResolver]
+// and an [Authorizer] is lazily initialized for each instance.
+type MultiAuthorizer[T Ctx] struct {
+	ctx          context.Context
+	resolve      InstanceResolver
+	initVerifier VerifierInitializer[T]
+	options      []Option[T]
+
+	mutex       sync.Mutex
+	authorizers map[string]*instanceAuthorizer[T]
+}
+
+// compile-time check that MultiAuthorizer implements AuthorizationChecker
+var _ AuthorizationChecker[Ctx] = (*MultiAuthorizer[Ctx])(nil)
+
+type instanceAuthorizer[T Ctx] struct {
+	mutex      sync.Mutex
+	authorizer *Authorizer[T]
+}
+
+// NewMulti creates a [MultiAuthorizer] using the [InstanceResolver] (e.g. [AllowInstances]) to select the instance.
+// The provided [VerifierInitializer] and options are used to initialize the [Authorizer] of each instance
+// on its first request. The context is used for their initialization and should live as long as the MultiAuthorizer,
+// since verifiers might run background tasks (e.g. refreshing keys).
+func NewMulti[T Ctx](ctx context.Context, resolve InstanceResolver, initVerifier VerifierInitializer[T], options ...Option[T]) *MultiAuthorizer[T] {
+	return &MultiAuthorizer[T]{
+		ctx:          ctx,
+		resolve:      resolve,
+		initVerifier: initVerifier,
+		options:      options,
+		authorizers:  make(map[string]*instanceAuthorizer[T]),
+	}
+}
+
+// CheckAuthorization resolves the instance by the issuer of the token and the host of the request (see [WithRequest])
+// and will verify the token using the [Authorizer] of the instance. If the authorization context implements [InstanceCtx],
+// the resolved instance is set before the checks are evaluated, so they can access it on the provided context.
+func (m *MultiAuthorizer[T]) CheckAuthorization(ctx context.Context, token string, options ...CheckOption) (authCtx T, err error) {
+	var t T
+	_, accessToken, err := splitAuthorizationHeader(token)
+	if err != nil {
+		return t, NewErrorUnauthorized(err)
+	}
+	instance, err := m.resolve(ctx, tokenIssuer(accessToken), requestHost(ctx))
+	if err != nil {
+		return t, NewErrorUnauthorized(err)
+	}
+	authorizer, err := m.authorizer(instance)
+	if err != nil {
+		if isServerError(err) {
+			return t, NewErrorServiceUnavailable(err)
+		}
+		return t, NewErrorUnauthorized(err)
+	}
+	return authorizer.CheckAuthorization(ctx, token, append([]CheckOption{withInstance(instance)}, options...)...)
+}
+
+// withInstance sets the instance on the authorization context (see [InstanceCtx]).
+// It is passed as first [CheckOption], so the instance is set before any other check is evaluated.
+func withInstance(instance *zitadel.Zitadel) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			if instanceCtx, ok := authCtx.(InstanceCtx); ok {
+				instanceCtx.SetInstance(instance)
+			}
+			return nil
+		})
+	}
+}
+
+// authorizer returns the [Authorizer] of the instance and initializes it if needed.
+// A failed initialization is not cached, so that it's retried on the next request.
+func (m *MultiAuthorizer[T]) authorizer(instance *zitadel.Zitadel) (*Authorizer[T], error) {
+	m.mutex.Lock()
+	entry, ok := m.authorizers[instance.Origin()]
+	if !ok {
+		entry = new(instanceAuthorizer[T])
+		m.authorizers[instance.Origin()] = entry
+	}
+	m.mutex.Unlock()
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.authorizer != nil {
+		return entry.authorizer, nil
+	}
+	authorizer, err := New(m.ctx, instance, m.initVerifier, m.options...)
+	if err != nil {
+		return nil, err
+	}
+	entry.authorizer = authorizer
+	return authorizer, nil
+}
+
+// tokenIssuer returns the (unverified) `iss` claim of a JWT or an empty string for opaque tokens.
+func tokenIssuer(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.Issuer
+}
+
+// requestHost returns the host of the [*http.Request] provided by [WithRequest]
+// or of the [RequestInfo] provided by [WithRequestInfo] (e.g. the `:authority` of a gRPC call).
+func requestHost(ctx context.Context) string {
+	if req, ok := Request(ctx).(*http.Request); ok {
+		return req.Host
+	}
+	if info := RequestInfoFromContext(ctx); info != nil {
+		return info.Host
+	}
+	return ""
+}
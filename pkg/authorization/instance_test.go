@@ -0,0 +1,128 @@
+package authorization
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+func TestMultiAuthorizer_CheckAuthorization(t *testing.T) {
+	instance1 := zitadel.New("customer1.zitadel.cloud")
+	instance2 := zitadel.New("auth.customer2.com")
+	unavailable := zitadel.New("unavailable.zitadel.cloud")
+	initialized := make(map[string]int)
+	m := NewMulti(context.Background(), AllowInstances(instance1, instance2, unavailable),
+		func(_ context.Context, instance *zitadel.Zitadel) (Verifier[*testCtx], error) {
+			initialized[instance.Domain()]++
+			if instance == unavailable {
+				return nil, NewErrorHTTPStatus(http.StatusServiceUnavailable, errors.New("unavailable"))
+			}
+			return &testVerifier[*testCtx]{ctx: &testCtx{isAuthorized: true}}, nil
+		},
+	)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		token        string
+		wantInstance *zitadel.Zitadel
+		wantErr      error
+	}{
+		{
+			name:         "issuer of jwt",
+			ctx:          context.Background(),
+			token:        "Bearer " + testJWT("https://auth.customer2.com"),
+			wantInstance: instance2,
+		},
+		{
+			name:         "host of http request",
+			ctx:          WithRequest(context.Background(), httptest.NewRequest("GET", "https://customer1.zitadel.cloud:8443/api", nil)),
+			token:        "Bearer opaque",
+			wantInstance: instance1,
+		},
+		{
+			name:         "host of request info",
+			ctx:          WithRequestInfo(context.Background(), &RequestInfo{Host: "auth.customer2.com"}),
+			token:        "Bearer opaque",
+			wantInstance: instance2,
+		},
+		{
+			name:         "issuer of jwt on unknown host",
+			ctx:          WithRequest(context.Background(), httptest.NewRequest("GET", "https://api.example.com/api", nil)),
+			token:        "Bearer " + testJWT("https://auth.customer2.com"),
+			wantInstance: instance2,
+		},
+		{
+			name:         "issuer of jwt matching host",
+			ctx:          WithRequest(context.Background(), httptest.NewRequest("GET", "https://auth.customer2.com/api", nil)),
+			token:        "Bearer " + testJWT("https://auth.customer2.com"),
+			wantInstance: instance2,
+		},
+		{
+			name:    "issuer of jwt of other instance than host",
+			ctx:     WithRequest(context.Background(), httptest.NewRequest("GET", "https://customer1.zitadel.cloud/api", nil)),
+			token:   "Bearer " + testJWT("https://auth.customer2.com"),
+			wantErr: NewErrorUnauthorized(ErrUnknownInstance),
+		},
+		{
+			name:    "unknown issuer",
+			ctx:     context.Background(),
+			token:   "Bearer " + testJWT("https://other.zitadel.cloud"),
+			wantErr: NewErrorUnauthorized(ErrUnknownInstance),
+		},
+		{
+			name:    "missing token",
+			ctx:     context.Background(),
+			token:   "",
+			wantErr: NewErrorUnauthorized(ErrMissingToken),
+		},
+		{
+			name:    "initialization fails",
+			ctx:     context.Background(),
+			token:   "Bearer " + testJWT("https://unavailable.zitadel.cloud"),
+			wantErr: NewErrorServiceUnavailable(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authCtx, err := m.CheckAuthorization(tt.ctx, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, tt.wantInstance, authCtx.Instance())
+			assert.Same(t, tt.wantInstance, Instance(WithAuthContext(context.Background(), authCtx)))
+		})
+	}
+	t.Run("instance in checks", func(t *testing.T) {
+		var checked *zitadel.Zitadel
+		_, err := m.CheckAuthorization(context.Background(), "Bearer "+testJWT("https://customer1.zitadel.cloud"), func(checks *Check[Ctx]) {
+			checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+				checked = authCtx.(InstanceCtx).Instance()
+				return nil
+			})
+		})
+		require.NoError(t, err)
+		assert.Same(t, instance1, checked)
+	})
+	_, err := m.CheckAuthorization(context.Background(), "Bearer "+testJWT("https://unavailable.zitadel.cloud"))
+	assert.Error(t, err)
+	assert.Equal(t, map[string]int{
+		"customer1.zitadel.cloud":   1,
+		"auth.customer2.com":        1,
+		"unavailable.zitadel.cloud": 2,
+	}, initialized)
+}
+
+func testJWT(issuer string) string {
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"`+issuer+`"}`)) + ".signature"
+}
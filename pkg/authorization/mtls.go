@@ -0,0 +1,122 @@
+package authorization
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+var (
+	ErrMissingClientCertificate = errors.New("missing client certificate")
+	ErrCertificateMismatch      = errors.New("access token is not bound to the client certificate")
+)
+
+// CertificateBoundCtx is an optional extension of [Ctx] providing the certificate binding of an access token (RFC 8705).
+// It is required by [WithCertificateBinding].
+type CertificateBoundCtx interface {
+	// CertificateThumbprint returns the `cnf.x5t#S256` claim (the base64url encoded SHA-256 thumbprint of the client certificate)
+	// or an empty string if the token is not certificate-bound.
+	CertificateThumbprint() string
+}
+
+// CertificateBindingOption allows customization of [WithCertificateBinding].
+type CertificateBindingOption func(*certificateBinding)
+
+// WithRequiredCertificateBinding rejects access tokens, which are not bound to a client certificate.
+// By default, such tokens are accepted.
+func WithRequiredCertificateBinding() CertificateBindingOption {
+	return func(b *certificateBinding) {
+		b.required = true
+	}
+}
+
+// WithCertificateBinding wraps the [Verifier] of the provided [VerifierInitializer] (e.g. a JWT or introspection verifier)
+// to verify certificate-bound access tokens (RFC 8705): if the token has a `cnf.x5t#S256` claim, it must match the
+// client certificate of the mutual TLS connection, taken from the [*http.Request] (see [WithRequest])
+// or the [RequestInfo] (e.g. of the gRPC peer provided by the gRPC interceptor).
+// A missing certificate returns an [ErrMissingClientCertificate], a different one an [ErrCertificateMismatch],
+// which both result in an [UnauthorizedErr].
+// The wrapped verifier is provided as [WrappingVerifier], so other wrappers (e.g. the JWT validation options
+// of the oauth package) can be applied in any order.
+func WithCertificateBinding[T Ctx](initVerifier VerifierInitializer[T], options ...CertificateBindingOption) VerifierInitializer[T] {
+	return func(ctx context.Context, zitadel *zitadel.Zitadel) (Verifier[T], error) {
+		verifier, err := initVerifier(ctx, zitadel)
+		if err != nil {
+			return nil, err
+		}
+		binding := &certificateBinding{}
+		for _, option := range options {
+			option(binding)
+		}
+		return &certificateBoundVerifier[T]{
+			verifier: verifier,
+			binding:  binding,
+		}, nil
+	}
+}
+
+type certificateBinding struct {
+	required bool
+}
+
+type certificateBoundVerifier[T Ctx] struct {
+	verifier Verifier[T]
+	binding  *certificateBinding
+}
+
+// Unwrap implements [WrappingVerifier].
+func (v *certificateBoundVerifier[T]) Unwrap() Verifier[T] {
+	return v.verifier
+}
+
+// CheckAuthorization implements [Verifier] by verifying the binding of the token verified by the wrapped [Verifier].
+func (v *certificateBoundVerifier[T]) CheckAuthorization(ctx context.Context, authorizationToken string) (authCtx T, err error) {
+	authCtx, err = v.verifier.CheckAuthorization(ctx, authorizationToken)
+	if err != nil {
+		return authCtx, err
+	}
+	var t T
+	boundCtx, ok := any(authCtx).(CertificateBoundCtx)
+	if !ok {
+		return t, ErrUnsupportedContext
+	}
+	thumbprint := boundCtx.CertificateThumbprint()
+	if thumbprint == "" {
+		if v.binding.required {
+			return t, fmt.Errorf("%w: token is not certificate-bound", ErrCertificateMismatch)
+		}
+		return authCtx, nil
+	}
+	certificate := clientCertificate(ctx)
+	if certificate == nil {
+		return t, ErrMissingClientCertificate
+	}
+	hash := sha256.Sum256(certificate.Raw)
+	expected := base64.RawURLEncoding.EncodeToString(hash[:])
+	if subtle.ConstantTimeCompare([]byte(thumbprint), []byte(expected)) != 1 {
+		return t, ErrCertificateMismatch
+	}
+	return authCtx, nil
+}
+
+// clientCertificate returns the (leaf) client certificate of the TLS connection
+// of the [*http.Request] provided by [WithRequest] or of the [RequestInfo] provided by [WithRequestInfo].
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	if req, ok := Request(ctx).(*http.Request); ok {
+		if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+			return nil
+		}
+		return req.TLS.PeerCertificates[0]
+	}
+	if info := RequestInfoFromContext(ctx); info != nil {
+		return info.ClientCertificate
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package authorization
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+func TestWithCertificateBinding(t *testing.T) {
+	certificate := testCertificate(t)
+	otherCertificate := testCertificate(t)
+	hash := sha256.Sum256(certificate.Raw)
+	thumbprint := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	tests := []struct {
+		name        string
+		options     []CertificateBindingOption
+		thumbprint  string
+		certificate *x509.Certificate
+		wantErr     error
+	}{
+		{
+			name:        "certificate matches",
+			thumbprint:  thumbprint,
+			certificate: certificate,
+		},
+		{
+			name:        "other certificate",
+			thumbprint:  thumbprint,
+			certificate: otherCertificate,
+			wantErr:     ErrCertificateMismatch,
+		},
+		{
+			name:       "missing certificate",
+			thumbprint: thumbprint,
+			wantErr:    ErrMissingClientCertificate,
+		},
+		{
+			name:        "unbound token",
+			certificate: certificate,
+		},
+		{
+			name:        "unbound token, binding required",
+			options:     []CertificateBindingOption{WithRequiredCertificateBinding()},
+			certificate: certificate,
+			wantErr:     ErrCertificateMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(context.Background(), zitadel.New("zitadel.invalid"), WithCertificateBinding(
+				func(context.Context, *zitadel.Zitadel) (Verifier[*testCtx], error) {
+					return &testVerifier[*testCtx]{ctx: &testCtx{isAuthorized: true, thumbprint: tt.thumbprint}}, nil
+				},
+				tt.options...,
+			))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+			if tt.certificate != nil {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.certificate}}
+			}
+			for name, ctx := range map[string]context.Context{
+				"http":         WithRequest(context.Background(), req),
+				"request info": WithRequestInfo(context.Background(), &RequestInfo{ClientCertificate: tt.certificate}),
+			} {
+				t.Run(name, func(t *testing.T) {
+					_, err := a.CheckAuthorization(ctx, "Bearer token")
+					if tt.wantErr != nil {
+						assert.ErrorIs(t, err, NewErrorUnauthorized(tt.wantErr))
+						return
+					}
+					assert.NoError(t, err)
+				})
+			}
+		})
+	}
+}
+
+func testCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate
+}
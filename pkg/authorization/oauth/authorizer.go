@@ -3,7 +3,6 @@ package oauth
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 
 	oidc_client "github.com/zitadel/oidc/v3/pkg/client"
@@ -25,20 +24,27 @@ var (
 // Use [WithIntrospection] for implementation.
 type IntrospectionVerification[T any] struct {
 	rs.ResourceServer
+	flight *flightGroup[T]
 }
 
 // WithIntrospection creates the OAuth2 Introspection implementation of the [authorization.Verifier] interface.
 // The introspection endpoint itself requires some [IntrospectionAuthentication] of the client.
 // Possible implementation are [JWTProfileIntrospectionAuthentication] and [ClientIDSecretIntrospectionAuthentication].
-func WithIntrospection[T authorization.Ctx](auth IntrospectionAuthentication) authorization.VerifierInitializer[T] {
+// Options such as [WithRequestCoalescing] allow further customization.
+func WithIntrospection[T authorization.Ctx](auth IntrospectionAuthentication, options ...IntrospectionOption) authorization.VerifierInitializer[T] {
+	config := newIntrospectionConfig(options)
 	return func(ctx context.Context, zitadel *zitadel.Zitadel) (authorization.Verifier[T], error) {
 		resourceServer, err := auth(ctx, zitadel.Origin())
 		if err != nil {
 			return nil, err
 		}
-		return &IntrospectionVerification[T]{
+		verification := &IntrospectionVerification[T]{
 			ResourceServer: resourceServer,
-		}, nil
+		}
+		if config.coalesce {
+			verification.flight = newFlightGroup[T](config.coalesceTimeout)
+		}
+		return verification, nil
 	}
 }
 
@@ -93,9 +99,11 @@ func (i *IntrospectionVerification[T]) CheckAuthorization(ctx context.Context, a
 	if !ok {
 		return resp, ErrInvalidAuthorizationHeader
 	}
-	resp, err = rs.Introspect[T](ctx, i.ResourceServer, strings.TrimSpace(accessToken))
-	if err != nil {
-		return resp, fmt.Errorf("%w: %v", ErrIntrospectionFailed, err)
+	accessToken = strings.TrimSpace(accessToken)
+	if i.flight == nil {
+		return introspect[T](ctx, i.ResourceServer, accessToken)
 	}
-	return resp, nil
+	return i.flight.do(ctx, accessToken, func(ctx context.Context) (T, error) {
+		return introspect[T](ctx, i.ResourceServer, accessToken)
+	})
 }
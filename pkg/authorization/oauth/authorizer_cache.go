@@ -2,7 +2,6 @@ package oauth
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
@@ -28,24 +27,31 @@ type TokenCache[T any] interface {
 // network calls for identical tokens within a configured TTL. If cache is nil,
 // all calls hit the introspection endpoint.
 type IntrospectionVerificationWithCache[T any] struct {
-	rs    rs.ResourceServer
-	cache TokenCache[T]
-	ttl   time.Duration
+	rs     rs.ResourceServer
+	cache  TokenCache[T]
+	ttl    time.Duration
+	flight *flightGroup[T]
 }
 
 // NewIntrospectionVerificationWithCache constructs a verifier that uses the
 // provided ResourceServer for introspection and an optional cache for reusing
 // results for the specified TTL. If cache is nil, no caching occurs.
+// Options such as [WithRequestCoalescing] allow further customization.
 func NewIntrospectionVerificationWithCache[T any](
 	rs rs.ResourceServer,
 	cache TokenCache[T],
 	ttl time.Duration,
+	options ...IntrospectionOption,
 ) *IntrospectionVerificationWithCache[T] {
-	return &IntrospectionVerificationWithCache[T]{
+	verification := &IntrospectionVerificationWithCache[T]{
 		rs:    rs,
 		cache: cache,
 		ttl:   ttl,
 	}
+	if config := newIntrospectionConfig(options); config.coalesce {
+		verification.flight = newFlightGroup[T](config.coalesceTimeout)
+	}
+	return verification
 }
 
 // CheckAuthorization validates authorizationToken. The value must be prefixed
@@ -69,17 +75,26 @@ func (v *IntrospectionVerificationWithCache[T]) CheckAuthorization(
 
 	if v.cache != nil {
 		if cached, found := v.cache.Get(token); found {
-			return cached, nil
+			return cloneValue(cached), nil
 		}
 	}
 
-	resp, err = rs.Introspect[T](ctx, v.rs, token)
-	if err != nil {
-		return zero, fmt.Errorf("%w: %v", ErrIntrospectionFailed, err)
+	if v.flight == nil {
+		return v.introspect(ctx, token)
 	}
+	return v.flight.do(ctx, token, func(ctx context.Context) (T, error) {
+		return v.introspect(ctx, token)
+	})
+}
 
+// introspect calls the introspection endpoint and stores the response in the cache (if configured).
+func (v *IntrospectionVerificationWithCache[T]) introspect(ctx context.Context, token string) (resp T, err error) {
+	resp, err = introspect[T](ctx, v.rs, token)
+	if err != nil {
+		return resp, err
+	}
 	if v.cache != nil {
 		v.cache.Set(token, resp, v.ttl)
 	}
-	return resp, nil
+	return cloneValue(resp), nil
 }
@@ -3,11 +3,16 @@ package oauth
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
 )
 
 func TestIntrospectionVerification_CheckAuthorization(t *testing.T) {
@@ -130,3 +135,40 @@ func (m *mockTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
 		Body:       responseBody,
 	}, nil
 }
+
+func TestIntrospectionVerification_CheckAuthorization_TypedErrors(t *testing.T) {
+	t.Run("http status is provided", func(t *testing.T) {
+		i := IntrospectionVerification[*introspection]{
+			ResourceServer: &resourceServer{
+				client: mockClient([]byte(`upstream unavailable`), 503),
+			},
+		}
+		_, err := i.CheckAuthorization(context.Background(), "Bearer token")
+		assert.ErrorIs(t, err, ErrIntrospectionFailed)
+		var statusErr *authorization.HTTPStatusErr
+		if assert.ErrorAs(t, err, &statusErr) {
+			assert.Equal(t, 503, statusErr.StatusCode)
+			assert.True(t, statusErr.IsServerError())
+		}
+	})
+	t.Run("network failure is kept", func(t *testing.T) {
+		i := IntrospectionVerification[*introspection]{
+			ResourceServer: &resourceServer{
+				client: &http.Client{Transport: &failingTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}},
+			},
+		}
+		_, err := i.CheckAuthorization(context.Background(), "Bearer token")
+		assert.ErrorIs(t, err, ErrIntrospectionFailed)
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		var statusErr *authorization.HTTPStatusErr
+		assert.False(t, errors.As(err, &statusErr))
+	})
+}
+
+type failingTransport struct {
+	err error
+}
+
+func (f *failingTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
+	return nil, f.err
+}
@@ -0,0 +1,290 @@
+package oauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+// Claims of ZITADEL tokens and introspection responses.
+const (
+	ClaimResourceOwnerID            = "urn:zitadel:iam:user:resourceowner:id"
+	ClaimResourceOwnerName          = "urn:zitadel:iam:user:resourceowner:name"
+	ClaimResourceOwnerPrimaryDomain = "urn:zitadel:iam:user:resourceowner:primary_domain"
+	ClaimUserMetadata               = "urn:zitadel:iam:user:metadata"
+	ClaimProjectRoles               = "urn:zitadel:iam:org:project:roles"
+	ClaimSessionID                  = "sid"
+	ClaimPreferredUsername          = "preferred_username"
+	ClaimActor                      = "act"
+	ClaimConfirmation               = "cnf"
+
+	claimProjectRolesPrefix = "urn:zitadel:iam:org:project:"
+	claimProjectRolesSuffix = ":roles"
+)
+
+var (
+	ErrInvalidMetadata = errors.New("invalid metadata")
+)
+
+// RoleGrant is a role granted to the user in an organization.
+type RoleGrant struct {
+	// ProjectID is set for roles of the `urn:zitadel:iam:org:project:{projectID}:roles` claim
+	// and empty for roles of the `urn:zitadel:iam:org:project:roles` claim (roles of the requested project).
+	ProjectID          string
+	Role               string
+	OrganizationID     string
+	OrganizationDomain string
+}
+
+// ResourceOwnerName returns the name of the organization of the user
+// (`urn:zitadel:iam:user:resourceowner:name` claim).
+func (c *IntrospectionContext) ResourceOwnerName() string {
+	return c.stringClaim(ClaimResourceOwnerName)
+}
+
+// ResourceOwnerPrimaryDomain returns the primary domain of the organization of the user
+// (`urn:zitadel:iam:user:resourceowner:primary_domain` claim).
+func (c *IntrospectionContext) ResourceOwnerPrimaryDomain() string {
+	return c.stringClaim(ClaimResourceOwnerPrimaryDomain)
+}
+
+// SessionID returns the id of the session the token was issued for (`sid` claim).
+func (c *IntrospectionContext) SessionID() string {
+	return c.stringClaim(ClaimSessionID)
+}
+
+// GetPreferredUsername returns the `preferred_username` claim or the `username` of the introspection response.
+func (c *IntrospectionContext) GetPreferredUsername() string {
+	if c == nil {
+		return ""
+	}
+	if c.PreferredUsername != "" {
+		return c.PreferredUsername
+	}
+	if c.Username != "" {
+		return c.Username
+	}
+	return c.stringClaim(ClaimPreferredUsername)
+}
+
+// ActorClaims returns the `act` claim of tokens issued by token exchange or impersonation
+// or nil if the token was not issued on behalf of another user.
+func (c *IntrospectionContext) ActorClaims() *oidc.ActorClaims {
+	if c == nil {
+		return nil
+	}
+	if c.Actor != nil {
+		return c.Actor
+	}
+	return actorFromClaim(c.Claims[ClaimActor])
+}
+
+// ActorChain implements [authorization.ActorCtx] by returning the actors of the (nested) `act` claim.
+func (c *IntrospectionContext) ActorChain() []authorization.Actor {
+	var actors []authorization.Actor
+	for actor := c.ActorClaims(); actor != nil; actor = actor.Actor {
+		actors = append(actors, authorization.Actor{
+			Subject: actor.Subject,
+			Issuer:  actor.Issuer,
+		})
+	}
+	return actors
+}
+
+// AllClaims returns all claims of the token or introspection response, including the standard claims
+// (e.g. `sub`, `aud` and `scope`) in their JSON representation.
+func (c *IntrospectionContext) AllClaims() map[string]any {
+	if c == nil {
+		return nil
+	}
+	// marshal a copy, since [oidc.IntrospectionResponse.MarshalJSON] modifies the username
+	resp := c.IntrospectionResponse
+	data, err := json.Marshal(&resp)
+	if err != nil {
+		return nil
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// JWKThumbprint implements [authorization.DPoPCtx] by returning the `jkt` member of the `cnf` claim
+// of a DPoP sender-constrained token.
+func (c *IntrospectionContext) JWKThumbprint() string {
+	if c == nil {
+		return ""
+	}
+	confirmation, _ := c.Claims[ClaimConfirmation].(map[string]any)
+	jkt, _ := confirmation["jkt"].(string)
+	return jkt
+}
+
+// CertificateThumbprint implements [authorization.CertificateBoundCtx] by returning the `x5t#S256` member
+// of the `cnf` claim of a certificate-bound token.
+func (c *IntrospectionContext) CertificateThumbprint() string {
+	if c == nil {
+		return ""
+	}
+	confirmation, _ := c.Claims[ClaimConfirmation].(map[string]any)
+	thumbprint, _ := confirmation["x5t#S256"].(string)
+	return thumbprint
+}
+
+// Metadata returns the base64 decoded metadata of the user (`urn:zitadel:iam:user:metadata` claim).
+func (c *IntrospectionContext) Metadata() (map[string][]byte, error) {
+	if c == nil {
+		return nil, nil
+	}
+	claim, ok := c.Claims[ClaimUserMetadata].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	metadata := make(map[string][]byte, len(claim))
+	for key, value := range claim {
+		encoded, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: value of %s is not a string", ErrInvalidMetadata, key)
+		}
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+		if err != nil {
+			if decoded, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+				return nil, fmt.Errorf("%w: value of %s: %w", ErrInvalidMetadata, key, err)
+			}
+		}
+		metadata[key] = decoded
+	}
+	return metadata, nil
+}
+
+// RoleGrants returns all roles granted to the user, listed per project and organization,
+// sorted by project, role and organization.
+func (c *IntrospectionContext) RoleGrants() []RoleGrant {
+	if c == nil {
+		return nil
+	}
+	var grants []RoleGrant
+	for claim, value := range c.Claims {
+		projectID, ok := projectIDFromRolesClaim(claim)
+		if !ok {
+			continue
+		}
+		roles, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		for role, orgs := range roles {
+			organisations, ok := orgs.(map[string]any)
+			if !ok {
+				continue
+			}
+			for orgID, domain := range organisations {
+				orgDomain, _ := domain.(string)
+				grants = append(grants, RoleGrant{
+					ProjectID:          projectID,
+					Role:               role,
+					OrganizationID:     orgID,
+					OrganizationDomain: orgDomain,
+				})
+			}
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].ProjectID != grants[j].ProjectID {
+			return grants[i].ProjectID < grants[j].ProjectID
+		}
+		if grants[i].Role != grants[j].Role {
+			return grants[i].Role < grants[j].Role
+		}
+		return grants[i].OrganizationID < grants[j].OrganizationID
+	})
+	return grants
+}
+
+// Roles implements [authorization.RolesCtx] by returning the roles of the `urn:zitadel:iam:org:project:roles` claim.
+func (c *IntrospectionContext) Roles() []string {
+	return c.roles(func(grant RoleGrant) bool {
+		return grant.ProjectID == ""
+	})
+}
+
+// RolesInOrganization implements [authorization.RolesCtx] by returning the roles of the `urn:zitadel:iam:org:project:roles` claim
+// granted in the organization.
+func (c *IntrospectionContext) RolesInOrganization(organizationID string) []string {
+	return c.roles(func(grant RoleGrant) bool {
+		return grant.ProjectID == "" && grant.OrganizationID == organizationID
+	})
+}
+
+// RolesInProject implements [authorization.RolesCtx] by returning the roles of the `urn:zitadel:iam:org:project:{projectID}:roles` claim.
+func (c *IntrospectionContext) RolesInProject(projectID string) []string {
+	return c.roles(func(grant RoleGrant) bool {
+		return grant.ProjectID == projectID
+	})
+}
+
+// roles returns the distinct roles of the matching [RoleGrant].
+func (c *IntrospectionContext) roles(match func(RoleGrant) bool) []string {
+	var roles []string
+	for _, grant := range c.RoleGrants() {
+		if match(grant) && !slices.Contains(roles, grant.Role) {
+			roles = append(roles, grant.Role)
+		}
+	}
+	return roles
+}
+
+func (c *IntrospectionContext) stringClaim(claim string) string {
+	if c == nil {
+		return ""
+	}
+	value, _ := c.Claims[claim].(string)
+	return value
+}
+
+// projectIDFromRolesClaim returns the projectID of a `urn:zitadel:iam:org:project:{projectID}:roles` claim,
+// an empty projectID for the `urn:zitadel:iam:org:project:roles` claim and false for any other claim.
+func projectIDFromRolesClaim(claim string) (string, bool) {
+	if claim == ClaimProjectRoles {
+		return "", true
+	}
+	projectID, ok := strings.CutPrefix(claim, claimProjectRolesPrefix)
+	if !ok {
+		return "", false
+	}
+	projectID, ok = strings.CutSuffix(projectID, claimProjectRolesSuffix)
+	return projectID, ok && projectID != "" && !strings.Contains(projectID, ":")
+}
+
+// actorFromClaim converts an untyped `act` claim (e.g. of the additional claims of a JWT) into [oidc.ActorClaims].
+func actorFromClaim(claim any) *oidc.ActorClaims {
+	act, ok := claim.(map[string]any)
+	if !ok {
+		return nil
+	}
+	actor := &oidc.ActorClaims{
+		Actor:  actorFromClaim(act[ClaimActor]),
+		Claims: make(map[string]any),
+	}
+	for key, value := range act {
+		switch key {
+		case "iss":
+			actor.Issuer, _ = value.(string)
+		case "sub":
+			actor.Subject, _ = value.(string)
+		case ClaimActor:
+		default:
+			actor.Claims[key] = value
+		}
+	}
+	return actor
+}
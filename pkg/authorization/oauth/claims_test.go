@@ -0,0 +1,136 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+var zitadelClaims = map[string]any{
+	"sid":                "session",
+	"cnf":                map[string]any{"jkt": "thumbprint", "x5t#S256": "certificate"},
+	"preferred_username": "minnie@mouse.com",
+	"act": map[string]any{
+		"iss": offlineIssuer,
+		"sub": "admin",
+		"act": map[string]any{"sub": "service"},
+	},
+	"urn:zitadel:iam:user:resourceowner:id":             "org1",
+	"urn:zitadel:iam:user:resourceowner:name":           "Org 1",
+	"urn:zitadel:iam:user:resourceowner:primary_domain": "org1.zitadel.cloud",
+	"urn:zitadel:iam:user:metadata": map[string]any{
+		"plan":  "cHJv",
+		"other": "Pz8_",
+	},
+	"urn:zitadel:iam:org:project:roles": map[string]any{
+		"admin": map[string]any{"org1": "org1.zitadel.cloud"},
+	},
+	"urn:zitadel:iam:org:project:project1:roles": map[string]any{
+		"admin":  map[string]any{"org1": "org1.zitadel.cloud"},
+		"reader": map[string]any{"org2": "org2.zitadel.cloud", "org1": "org1.zitadel.cloud"},
+	},
+}
+
+func TestIntrospectionContext_Claims(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	token, err := signTestJWT(signParams{
+		KeyID:      key.KID(),
+		PrivateKey: key.Private(),
+		Issuer:     offlineIssuer,
+		Subject:    "user",
+		Audience:   []string{"test-client-id"},
+		TTL:        time.Hour,
+		Claims:     zitadelClaims,
+	})
+	require.NoError(t, err)
+	verifier, err := oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromKeySet(testKeySet(key)))(context.Background(), zitadel.New("offline.invalid"))
+	require.NoError(t, err)
+	fromJWT, err := verifier.CheckAuthorization(context.Background(), "Bearer "+token)
+	require.NoError(t, err)
+
+	introspection := map[string]any{"active": true, "sub": "user"}
+	for k, v := range zitadelClaims {
+		introspection[k] = v
+	}
+	payload, err := json.Marshal(introspection)
+	require.NoError(t, err)
+	fromIntrospection := new(oauth.IntrospectionContext)
+	require.NoError(t, json.Unmarshal(payload, &fromIntrospection.IntrospectionResponse))
+
+	for name, ctx := range map[string]*oauth.IntrospectionContext{"jwt": fromJWT, "introspection": fromIntrospection} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, "org1", ctx.OrganizationID())
+			assert.Equal(t, "Org 1", ctx.ResourceOwnerName())
+			assert.Equal(t, "org1.zitadel.cloud", ctx.ResourceOwnerPrimaryDomain())
+			assert.Equal(t, "session", ctx.SessionID())
+			assert.Equal(t, "minnie@mouse.com", ctx.GetPreferredUsername())
+			assert.Equal(t, "user", ctx.AllClaims()["sub"])
+			assert.Equal(t, "thumbprint", ctx.JWKThumbprint())
+			assert.Equal(t, "certificate", ctx.CertificateThumbprint())
+			assert.Equal(t, "org1", ctx.AllClaims()["urn:zitadel:iam:user:resourceowner:id"])
+
+			metadata, err := ctx.Metadata()
+			require.NoError(t, err)
+			assert.Equal(t, map[string][]byte{"plan": []byte("pro"), "other": []byte("???")}, metadata)
+
+			actor := ctx.ActorClaims()
+			require.NotNil(t, actor)
+			assert.Equal(t, "admin", actor.Subject)
+			assert.Equal(t, offlineIssuer, actor.Issuer)
+			require.NotNil(t, actor.Actor)
+			assert.Equal(t, "service", actor.Actor.Subject)
+			assert.Equal(t, []authorization.Actor{{Subject: "admin", Issuer: offlineIssuer}, {Subject: "service"}}, ctx.ActorChain())
+
+			assert.Equal(t, []oauth.RoleGrant{
+				{Role: "admin", OrganizationID: "org1", OrganizationDomain: "org1.zitadel.cloud"},
+				{ProjectID: "project1", Role: "admin", OrganizationID: "org1", OrganizationDomain: "org1.zitadel.cloud"},
+				{ProjectID: "project1", Role: "reader", OrganizationID: "org1", OrganizationDomain: "org1.zitadel.cloud"},
+				{ProjectID: "project1", Role: "reader", OrganizationID: "org2", OrganizationDomain: "org2.zitadel.cloud"},
+			}, ctx.RoleGrants())
+			assert.Equal(t, []string{"admin"}, ctx.Roles())
+			assert.Equal(t, []string{"admin"}, ctx.RolesInOrganization("org1"))
+			assert.Empty(t, ctx.RolesInOrganization("org2"))
+			assert.Equal(t, []string{"admin", "reader"}, ctx.RolesInProject("project1"))
+		})
+	}
+}
+
+func TestIntrospectionContext_ClaimsEmpty(t *testing.T) {
+	for name, ctx := range map[string]*oauth.IntrospectionContext{
+		"nil":   nil,
+		"empty": {IntrospectionResponse: oidc.IntrospectionResponse{Active: true}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Empty(t, ctx.ResourceOwnerName())
+			assert.Empty(t, ctx.ResourceOwnerPrimaryDomain())
+			assert.Empty(t, ctx.SessionID())
+			assert.Empty(t, ctx.GetPreferredUsername())
+			assert.Nil(t, ctx.ActorClaims())
+			assert.Empty(t, ctx.ActorChain())
+			assert.Empty(t, ctx.RoleGrants())
+			assert.Empty(t, ctx.Roles())
+			assert.Empty(t, ctx.RolesInProject("project1"))
+			metadata, err := ctx.Metadata()
+			assert.NoError(t, err)
+			assert.Empty(t, metadata)
+		})
+	}
+}
+
+func TestIntrospectionContext_MetadataInvalid(t *testing.T) {
+	ctx := &oauth.IntrospectionContext{IntrospectionResponse: oidc.IntrospectionResponse{
+		Claims: map[string]any{"urn:zitadel:iam:user:metadata": map[string]any{"key": "not base64!"}},
+	}}
+	_, err := ctx.Metadata()
+	assert.ErrorIs(t, err, oauth.ErrInvalidMetadata)
+}
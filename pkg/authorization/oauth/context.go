@@ -1,11 +1,30 @@
 package oauth
 
-import "github.com/zitadel/oidc/v3/pkg/oidc"
+import (
+	"slices"
+	"time"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+var (
+	_ authorization.TokenCtx    = (*IntrospectionContext)(nil)
+	_ authorization.ActorCtx    = (*IntrospectionContext)(nil)
+	_ authorization.RolesCtx    = (*IntrospectionContext)(nil)
+	_ authorization.InstanceCtx = (*IntrospectionContext)(nil)
+	_ authorization.DPoPCtx     = (*IntrospectionContext)(nil)
+
+	_ authorization.CertificateBoundCtx = (*IntrospectionContext)(nil)
+)
 
 // IntrospectionContext implements the [authorization.Ctx] interface with the [oidc.IntrospectionResponse] as underlying data.
 type IntrospectionContext struct {
 	oidc.IntrospectionResponse
-	token string
+	token    string
+	instance *zitadel.Zitadel
 }
 
 // IsAuthorized implements [authorization.Ctx] by checking the `active` claim of the [oidc.IntrospectionResponse].
@@ -23,7 +42,7 @@ func (c *IntrospectionContext) OrganizationID() string {
 		return ""
 	}
 	// check for organization ID when using scope "urn:zitadel:iam:user:resourceowner"
-	orgID, _ := c.Claims["urn:zitadel:iam:user:resourceowner:id"].(string)
+	orgID, _ := c.Claims[ClaimResourceOwnerID].(string)
 	return orgID
 }
 
@@ -64,6 +83,50 @@ func (c *IntrospectionContext) IsGrantedRoleInProject(projectID, role, organisat
 	return len(organisations) > 0
 }
 
+// HasScope implements [authorization.TokenCtx] by checking if the `scope` claim contains the requested scope.
+func (c *IntrospectionContext) HasScope(scope string) bool {
+	if c == nil {
+		return false
+	}
+	return slices.Contains(c.Scope, scope)
+}
+
+// HasAudience implements [authorization.TokenCtx] by checking if the `aud` claim contains the requested audience.
+func (c *IntrospectionContext) HasAudience(audience string) bool {
+	if c == nil {
+		return false
+	}
+	return slices.Contains(c.Audience, audience)
+}
+
+// GetClientID implements [authorization.TokenCtx] by returning the `client_id` claim of the [oidc.IntrospectionResponse].
+func (c *IntrospectionContext) GetClientID() string {
+	if c == nil {
+		return ""
+	}
+	return c.ClientID
+}
+
+// ExpiresAt returns the expiry of the token (`exp` claim) or the zero time if not present.
+func (c *IntrospectionContext) ExpiresAt() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	return c.Expiration.AsTime()
+}
+
+// Clone returns a shallow copy of the context without the token, so that a shared (e.g. cached) context
+// can be handed to multiple callers without interfering calls of [IntrospectionContext.SetToken]
+// and without keeping the raw token.
+func (c *IntrospectionContext) Clone() *IntrospectionContext {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.token = ""
+	return &clone
+}
+
 func (c *IntrospectionContext) SetToken(token string) {
 	c.token = token
 }
@@ -72,8 +135,21 @@ func (c *IntrospectionContext) GetToken() string {
 	return c.token
 }
 
+// SetInstance implements [authorization.InstanceCtx].
+func (c *IntrospectionContext) SetInstance(instance *zitadel.Zitadel) {
+	c.instance = instance
+}
+
+// Instance implements [authorization.InstanceCtx] by returning the instance set by the [authorization.MultiAuthorizer].
+func (c *IntrospectionContext) Instance() *zitadel.Zitadel {
+	if c == nil {
+		return nil
+	}
+	return c.instance
+}
+
 func (c *IntrospectionContext) checkRoleClaim(role string) map[string]interface{} {
-	roles, ok := c.Claims["urn:zitadel:iam:org:project:roles"].(map[string]interface{})
+	roles, ok := c.Claims[ClaimProjectRoles].(map[string]interface{})
 	if !ok || len(roles) == 0 {
 		return nil
 	}
@@ -85,7 +161,7 @@ func (c *IntrospectionContext) checkRoleClaim(role string) map[string]interface{
 }
 
 func (c *IntrospectionContext) checkProjectRoleClaim(projectID, role, organisationID string) map[string]interface{} {
-	claimKey := "urn:zitadel:iam:org:project:" + projectID + ":roles"
+	claimKey := claimProjectRolesPrefix + projectID + claimProjectRolesSuffix
 	roles, ok := c.Claims[claimKey].(map[string]interface{})
 	if !ok || len(roles) == 0 {
 		return nil
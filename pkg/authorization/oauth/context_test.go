@@ -265,3 +265,24 @@ func TestIntrospectionContext_IsGrantedRoleInProject(t *testing.T) {
 		})
 	}
 }
+
+func TestIntrospectionContext_TokenCtx(t *testing.T) {
+	ctx := &IntrospectionContext{
+		IntrospectionResponse: oidc.IntrospectionResponse{
+			Active:   true,
+			Scope:    oidc.SpaceDelimitedArray{"openid", "profile"},
+			Audience: oidc.Audience{"client", "project"},
+			ClientID: "client",
+		},
+	}
+	assert.True(t, ctx.HasScope("profile"))
+	assert.False(t, ctx.HasScope("email"))
+	assert.True(t, ctx.HasAudience("project"))
+	assert.False(t, ctx.HasAudience("other"))
+	assert.Equal(t, "client", ctx.GetClientID())
+
+	var nilCtx *IntrospectionContext
+	assert.False(t, nilCtx.HasScope("openid"))
+	assert.False(t, nilCtx.HasAudience("client"))
+	assert.Empty(t, nilCtx.GetClientID())
+}
@@ -0,0 +1,28 @@
+package oauth
+
+import (
+	"time"
+)
+
+// WithKeySetClock replaces the clock of the [ManagedKeySet], so that tests don't need to wait for the refresh intervals.
+func WithKeySetClock(now func() time.Time) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.now = now
+	}
+}
+
+// WithKeySetMinRefreshFloor replaces the lower bound of the minimum refresh interval of the [ManagedKeySet],
+// so that tests can use shorter intervals.
+func WithKeySetMinRefreshFloor(floor time.Duration) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.minRefreshFloor = floor
+	}
+}
+
+// WithRevocationClock replaces the clock of the check interval of the [RevocationVerification],
+// so that tests don't need to wait for the interval.
+func WithRevocationClock(now func() time.Time) RevocationOption {
+	return func(c *revocationConfig) {
+		c.now = now
+	}
+}
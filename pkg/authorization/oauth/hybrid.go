@@ -0,0 +1,159 @@
+package oauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	gohttp "net/http"
+	"strings"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"github.com/zitadel/oidc/v3/pkg/op"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/client"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+// HybridVerification provides an [authorization.Verifier] implementation
+// for deployments with both JWT and opaque access tokens in use.
+// JWTs are validated locally using the [JWTVerification], while opaque tokens
+// are validated by calling the introspection endpoint.
+// Use [WithHybrid] or [DefaultHybridAuthorization] for implementation.
+type HybridVerification struct {
+	jwt              authorization.Verifier[*IntrospectionContext]
+	introspection    authorization.Verifier[*IntrospectionContext]
+	introspectTokens bool
+}
+
+var _ authorization.WrappingVerifier[*IntrospectionContext] = (*HybridVerification)(nil)
+
+// HybridOption allows customization of the [HybridVerification].
+type HybridOption func(*hybridConfig)
+
+type hybridConfig struct {
+	httpClient           *gohttp.Client
+	jwtOptions           []op.AccessTokenVerifierOpt
+	introspectionOptions []IntrospectionOption
+	introspectAll        bool
+}
+
+// WithHybridHTTPClient sets the HTTP client used for the OIDC discovery and the JWKS requests.
+func WithHybridHTTPClient(httpClient *gohttp.Client) HybridOption {
+	return func(c *hybridConfig) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHybridJWTOptions passes the [op.AccessTokenVerifierOpt] to the local JWT validation (see [WithJWT]).
+func WithHybridJWTOptions(options ...op.AccessTokenVerifierOpt) HybridOption {
+	return func(c *hybridConfig) {
+		c.jwtOptions = append(c.jwtOptions, options...)
+	}
+}
+
+// WithHybridIntrospectionOptions passes the [IntrospectionOption] to the introspection (see [WithIntrospection]).
+func WithHybridIntrospectionOptions(options ...IntrospectionOption) HybridOption {
+	return func(c *hybridConfig) {
+		c.introspectionOptions = append(c.introspectionOptions, options...)
+	}
+}
+
+// WithIntrospectAll additionally introspects JWTs after a successful local validation,
+// so that revoked tokens are detected before their expiry.
+// To introspect only periodically, use [WithRevocation] instead.
+func WithIntrospectAll() HybridOption {
+	return func(c *hybridConfig) {
+		c.introspectAll = true
+	}
+}
+
+// WithHybrid creates the [HybridVerification] implementation of the [authorization.Verifier] interface.
+// It accepts the clientID for the audience validation of JWTs and the [IntrospectionAuthentication]
+// for the introspection of opaque tokens.
+func WithHybrid(clientID string, auth IntrospectionAuthentication, options ...HybridOption) authorization.VerifierInitializer[*IntrospectionContext] {
+	config := new(hybridConfig)
+	for _, option := range options {
+		option(config)
+	}
+	initJWT := WithJWT(clientID, config.httpClient, config.jwtOptions...)
+	initIntrospection := WithIntrospection[*IntrospectionContext](auth, config.introspectionOptions...)
+
+	return func(ctx context.Context, zitadel *zitadel.Zitadel) (authorization.Verifier[*IntrospectionContext], error) {
+		jwtVerifier, err := initJWT(ctx, zitadel)
+		if err != nil {
+			return nil, err
+		}
+		introspectionVerifier, err := initIntrospection(ctx, zitadel)
+		if err != nil {
+			return nil, err
+		}
+		return &HybridVerification{
+			jwt:              jwtVerifier,
+			introspection:    introspectionVerifier,
+			introspectTokens: config.introspectAll,
+		}, nil
+	}
+}
+
+// DefaultHybridAuthorization is a short version of [WithHybrid] with the introspection authenticated
+// by a key.json read from a provided path (see [DefaultAuthorization]).
+func DefaultHybridAuthorization(clientID, path string, options ...HybridOption) authorization.VerifierInitializer[*IntrospectionContext] {
+	c, err := client.ConfigFromKeyFile(path)
+	if err != nil {
+		return func(ctx context.Context, _ *zitadel.Zitadel) (authorization.Verifier[*IntrospectionContext], error) {
+			return nil, err
+		}
+	}
+	return WithHybrid(clientID, IntrospectionAuthenticationJWTProfile(c), options...)
+}
+
+// CheckAuthorization implements the [authorization.Verifier] interface.
+// If the token is a JWT, it is validated locally and only introspected if [WithIntrospectAll] is set.
+// Any other (opaque) token is introspected.
+func (h *HybridVerification) CheckAuthorization(ctx context.Context, authorizationToken string) (*IntrospectionContext, error) {
+	accessToken, ok := strings.CutPrefix(authorizationToken, oidc.BearerToken)
+	if !ok {
+		return nil, ErrInvalidAuthorizationHeader
+	}
+	if !IsJWT(strings.TrimSpace(accessToken)) {
+		return h.introspection.CheckAuthorization(ctx, authorizationToken)
+	}
+	resp, err := h.jwt.CheckAuthorization(ctx, authorizationToken)
+	if err != nil || !h.introspectTokens {
+		return resp, err
+	}
+	return h.introspection.CheckAuthorization(ctx, authorizationToken)
+}
+
+// Unwrap implements the [authorization.WrappingVerifier] interface by returning the verifier of JWTs.
+func (h *HybridVerification) Unwrap() authorization.Verifier[*IntrospectionContext] {
+	return h.jwt
+}
+
+// IsJWT returns if the token is formatted as a JWS in compact serialization
+// (three base64url encoded parts, with a header specifying the signing algorithm).
+// It does not validate the token.
+func IsJWT(token string) bool {
+	return signingAlgorithm(token) != ""
+}
+
+// signingAlgorithm returns the `alg` of the header of a JWS in compact serialization
+// or an empty string if the token is not formatted as such.
+func signingAlgorithm(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ""
+	}
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err = json.Unmarshal(rawHeader, &header); err != nil {
+		return ""
+	}
+	return header.Algorithm
+}
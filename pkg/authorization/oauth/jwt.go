@@ -4,14 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	gohttp "net/http"
-	"slices"
 	"strings"
 
 	"github.com/zitadel/oidc/v3/pkg/client"
 	"github.com/zitadel/oidc/v3/pkg/op"
 
-	"github.com/zitadel/oidc/v3/pkg/client/rp"
 	"github.com/zitadel/oidc/v3/pkg/oidc"
 
 	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
@@ -26,10 +25,14 @@ var (
 // JWTVerification provides an [authorization.Verifier] implementation
 // by validating an Authorization header bearing a JWT locally.
 type JWTVerification struct {
-	verifier *op.AccessTokenVerifier
-	clientID string
+	verifier   *op.AccessTokenVerifier
+	keySet     oidc.KeySet
+	clientID   string
+	validation *JWTValidationOptions
 }
 
+var _ authorization.LoggingVerifier = (*JWTVerification)(nil)
+
 // WithJWT creates the local JWT validation implementation of the
 // [authorization.Verifier] interface. It is the recommended high-performance
 // method for securing high-throughput APIs.
@@ -49,14 +52,25 @@ func WithJWT(clientID string, httpClient *gohttp.Client, options ...op.AccessTok
 			return nil, fmt.Errorf("OIDC discovery failed: %w", err)
 		}
 
-		keySet := rp.NewRemoteKeySet(httpClient, discoveryConfig.JwksURI)
+		keySet := newRemoteKeySet(httpClient, discoveryConfig.JwksURI)
 
-		verifier := op.NewAccessTokenVerifier(discoveryConfig.Issuer, keySet, options...)
+		return newJWTVerification(discoveryConfig.Issuer, keySet, clientID, options...), nil
+	}
+}
 
-		return &JWTVerification{
-			verifier: verifier,
-			clientID: clientID,
-		}, nil
+func newJWTVerification(issuer string, keySet oidc.KeySet, clientID string, options ...op.AccessTokenVerifierOpt) *JWTVerification {
+	return &JWTVerification{
+		verifier: op.NewAccessTokenVerifier(issuer, &keySetErrRecorder{KeySet: keySet}, options...),
+		keySet:   keySet,
+		clientID: clientID,
+	}
+}
+
+// SetLogger implements the [authorization.LoggingVerifier] interface by passing the logger
+// to key sets logging on their own (e.g. the [FileKeySet]).
+func (j *JWTVerification) SetLogger(logger *slog.Logger) {
+	if loggingKeySet, ok := j.keySet.(interface{ SetLogger(*slog.Logger) }); ok {
+		loggingKeySet.SetLogger(logger)
 	}
 }
 
@@ -64,7 +78,8 @@ func WithJWT(clientID string, httpClient *gohttp.Client, options ...op.AccessTok
 // validates an access token from an "Authorization: Bearer <token>" header.
 //
 // The validation is performed locally using the cached JWKS keys. It checks
-// the token's signature, expiry, and issuer. On success, it returns an
+// the token's signature, expiry, issuer and audience, as well as the
+// [JWTValidationOptions] if applied with [WithJWTValidation]. On success, it returns an
 // [*IntrospectionContext] populated with the claims from the validated JWT.
 // This provides a fast, offline alternative to token introspection.
 func (j *JWTVerification) CheckAuthorization(ctx context.Context, authorizationToken string) (*IntrospectionContext, error) {
@@ -74,29 +89,36 @@ func (j *JWTVerification) CheckAuthorization(ctx context.Context, authorizationT
 	}
 	accessToken = strings.TrimSpace(accessToken)
 
+	if err := j.checkAlgorithm(accessToken); err != nil {
+		return nil, err
+	}
+	var keySetErr error
+	ctx = context.WithValue(ctx, keySetErrKey{}, &keySetErr)
 	claims, err := op.VerifyAccessToken[*oidc.AccessTokenClaims](ctx, accessToken, j.verifier)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
-	}
-
-	if len(claims.Audience) == 0 {
-		return nil, fmt.Errorf("%w: empty aud", ErrInvalidToken)
+		if keySetErr != nil {
+			// keep the type of the key set error (e.g. an unavailable JWKS endpoint)
+			return nil, fmt.Errorf("%w: %w", verificationErr(err), keySetErr)
+		}
+		return nil, verificationErr(err)
 	}
-	if !slices.Contains(claims.Audience, j.clientID) {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidAudience, j.clientID)
+	if err = j.validate(claims); err != nil {
+		return nil, err
 	}
 
 	resp := &IntrospectionContext{
 		IntrospectionResponse: oidc.IntrospectionResponse{
 			Active:     true,
+			Scope:      claims.Scopes,
 			Issuer:     claims.Issuer,
 			Subject:    claims.Subject,
 			Audience:   claims.Audience,
 			Expiration: claims.Expiration,
 			IssuedAt:   claims.IssuedAt,
 			NotBefore:  claims.NotBefore,
-			ClientID:   claims.ClientID,
+			ClientID:   clientIDFromClaims(claims),
 			JWTID:      claims.JWTID,
+			Actor:      claims.Actor,
 			Claims:     claims.Claims,
 		},
 	}
@@ -104,6 +126,15 @@ func (j *JWTVerification) CheckAuthorization(ctx context.Context, authorizationT
 	return resp, nil
 }
 
+// clientIDFromClaims returns the `client_id` claim of the token
+// and falls back to the authorized party (`azp`) if not present.
+func clientIDFromClaims(claims *oidc.AccessTokenClaims) string {
+	if claims.ClientID != "" {
+		return claims.ClientID
+	}
+	return claims.AuthorizedParty
+}
+
 // DefaultJWTAuthorization provides a simple initializer for the recommended
 // high-performance JWT validation method. It is a convenient wrapper around
 // WithJWT.
@@ -158,3 +158,44 @@ func TestWithJWT(t *testing.T) {
 		})
 	}
 }
+
+//goland:noinspection HttpUrlsUsage
+func TestWithJWT_KeySetUnavailable(t *testing.T) {
+	keyPair, err := NewTestKey(2048)
+	require.NoError(t, err, "failed to generate rsa key")
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/keys":
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"issuer":   "http://" + r.Host,
+				"jwks_uri": "http://" + r.Host + "/keys",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(mockServer.Close)
+
+	parsedURL, err := url.Parse(mockServer.URL)
+	require.NoError(t, err, "failed to parse mock server url")
+	z := zitadel.New(parsedURL.Hostname(), zitadel.WithInsecure(parsedURL.Port()))
+	authorizer, err := authorization.New(context.Background(), z, oauth.DefaultJWTAuthorization("test-client-id"))
+	require.NoError(t, err, "authorization.New with WithJWT failed")
+
+	signedToken, err := signTestJWT(signParams{
+		KeyID:      keyPair.KID(),
+		PrivateKey: keyPair.Private(),
+		Issuer:     mockServer.URL,
+		Subject:    "test-user-id",
+		Audience:   []string{"test-client-id"},
+		TTL:        time.Hour,
+	})
+	require.NoError(t, err, "failed to create test JWT")
+
+	_, err = authorizer.CheckAuthorization(context.Background(), "Bearer "+signedToken)
+	assert.ErrorIs(t, err, authorization.NewErrorServiceUnavailable(nil))
+	assert.ErrorIs(t, err, authorization.NewErrorHTTPStatus(http.StatusServiceUnavailable, nil))
+}
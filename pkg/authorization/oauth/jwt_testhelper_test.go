@@ -17,6 +17,8 @@ type signParams struct {
 	TTL        time.Duration
 	NotBefore  time.Duration
 	Algorithm  jwt.SigningMethod
+	IssuedAt   time.Duration
+	Claims     map[string]any
 }
 
 // signTestJWT builds a signed token for unit tests only, with configurable options.
@@ -27,7 +29,7 @@ func signTestJWT(p signParams) (string, error) {
 		Issuer:    p.Issuer,
 		Subject:   p.Subject,
 		Audience:  jwt.ClaimStrings(p.Audience),
-		IssuedAt:  jwt.NewNumericDate(now),
+		IssuedAt:  jwt.NewNumericDate(now.Add(p.IssuedAt)),
 		NotBefore: jwt.NewNumericDate(now.Add(p.NotBefore)),
 		ExpiresAt: jwt.NewNumericDate(now.Add(p.TTL)),
 	}
@@ -37,7 +39,23 @@ func signTestJWT(p signParams) (string, error) {
 		p.Algorithm = jwt.SigningMethodRS256
 	}
 
-	tok := jwt.NewWithClaims(p.Algorithm, claims)
+	var tok *jwt.Token
+	if p.Claims != nil {
+		mapClaims := jwt.MapClaims{
+			"iss": claims.Issuer,
+			"sub": claims.Subject,
+			"aud": claims.Audience,
+			"iat": claims.IssuedAt,
+			"nbf": claims.NotBefore,
+			"exp": claims.ExpiresAt,
+		}
+		for k, v := range p.Claims {
+			mapClaims[k] = v
+		}
+		tok = jwt.NewWithClaims(p.Algorithm, mapClaims)
+	} else {
+		tok = jwt.NewWithClaims(p.Algorithm, claims)
+	}
 	tok.Header["kid"] = p.KeyID
 
 	// Handle the "none" algorithm case, which is unsigned
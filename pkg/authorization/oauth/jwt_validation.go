@@ -0,0 +1,188 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrTokenExpired         = errors.New("token expired")
+	ErrTokenNotYetValid     = errors.New("token not yet valid")
+	ErrTokenTooOld          = errors.New("token too old")
+	ErrMissingClaim         = errors.New("missing claim")
+	ErrUnauthorizedParty    = errors.New("unauthorized party")
+	ErrUnsupportedVerifier  = errors.New("verifier does not support jwt validation options")
+)
+
+// JWTValidationOptions configures the validation of a JWT by the [JWTVerification] beyond its signature, issuer and expiry.
+// Use [WithJWTValidation] to apply them.
+//
+// Every failure is reported as [ErrInvalidToken] in combination with a specific error
+// (e.g. [ErrInvalidAudience], [ErrTokenTooOld] or [ErrMissingClaim]).
+type JWTValidationOptions struct {
+	// Audiences accepted in the `aud` claim, at least one of them must be present.
+	// If neither Audiences nor ProjectIDs are set, the clientID of the verifier is required.
+	Audiences []string
+	// ProjectIDs accepted in the `aud` claim, as requested by the [github.com/zitadel/zitadel-go/v3/pkg/client.ScopeProjectID] scope.
+	ProjectIDs []string
+	// SigningAlgorithms allowed for the signature of the token (e.g. "RS256"). Defaults to RS256, ES256 and PS256.
+	SigningAlgorithms []string
+	// MaxAge of the token based on its `iat` claim. A MaxAge of 0 disables the check.
+	MaxAge time.Duration
+	// Leeway allowed for clock skew when validating `exp`, `nbf` and `iat`.
+	Leeway time.Duration
+	// RequiredClaims that must be present in the token, e.g. `urn:zitadel:iam:user:resourceowner:id`.
+	RequiredClaims []string
+	// AuthorizedParties allowed as `client_id` and `azp` of the token. If empty, any client is accepted.
+	AuthorizedParties []string
+}
+
+// WithJWTValidation applies the [JWTValidationOptions] to the [JWTVerification]
+// created by the initializer (e.g. [WithJWT], [WithOfflineJWT], [WithManagedJWT] or [WithHybrid]).
+// The [JWTVerification] is also found through wrappers implementing [authorization.WrappingVerifier]
+// (e.g. [authorization.WithCertificateBinding] or [WithRevocation]), so the order of the wrappers does not matter.
+// If the initializer does not create a JWT based verifier, [ErrUnsupportedVerifier] is returned.
+func WithJWTValidation(initializer authorization.VerifierInitializer[*IntrospectionContext], options JWTValidationOptions) authorization.VerifierInitializer[*IntrospectionContext] {
+	return func(ctx context.Context, zitadel *zitadel.Zitadel) (authorization.Verifier[*IntrospectionContext], error) {
+		verifier, err := initializer(ctx, zitadel)
+		if err != nil {
+			return nil, err
+		}
+		j, ok := authorization.FindVerifier[*JWTVerification](verifier)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedVerifier, verifier)
+		}
+		j.applyValidation(options)
+		return verifier, nil
+	}
+}
+
+func (j *JWTVerification) applyValidation(options JWTValidationOptions) {
+	j.validation = &options
+	if len(options.SigningAlgorithms) > 0 {
+		j.verifier.SupportedSignAlgs = options.SigningAlgorithms
+	}
+	// the expiry is checked with the offset: a negative offset allows for clock skew
+	j.verifier.Offset = -options.Leeway
+}
+
+// checkAlgorithm checks the `alg` header of the token against the allowed signing algorithms
+// to report [ErrUnsupportedAlgorithm] (the token verification only reports a parsing error).
+func (j *JWTVerification) checkAlgorithm(token string) error {
+	if j.validation == nil || len(j.validation.SigningAlgorithms) == 0 {
+		return nil
+	}
+	if alg := signingAlgorithm(token); alg != "" && !slices.Contains(j.validation.SigningAlgorithms, alg) {
+		return fmt.Errorf("%w: %w: %s", ErrInvalidToken, ErrUnsupportedAlgorithm, alg)
+	}
+	return nil
+}
+
+// validate checks the claims of a verified token against the [JWTValidationOptions].
+func (j *JWTVerification) validate(claims *oidc.AccessTokenClaims) error {
+	if len(claims.Audience) == 0 {
+		return fmt.Errorf("%w: empty aud", ErrInvalidToken)
+	}
+	if j.validation == nil {
+		if !slices.Contains(claims.Audience, j.clientID) {
+			return fmt.Errorf("%w: %s", ErrInvalidAudience, j.clientID)
+		}
+		return nil
+	}
+	audiences := slices.Concat(j.validation.Audiences, j.validation.ProjectIDs)
+	if len(audiences) == 0 {
+		audiences = []string{j.clientID}
+	}
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(audiences, aud) }) {
+		return fmt.Errorf("%w: %w: expected one of %v", ErrInvalidToken, ErrInvalidAudience, audiences)
+	}
+
+	now := time.Now()
+	leeway := j.validation.Leeway
+	if notBefore := claims.NotBefore.AsTime(); !notBefore.IsZero() && notBefore.After(now.Add(leeway)) {
+		return fmt.Errorf("%w: %w: nbf %v", ErrInvalidToken, ErrTokenNotYetValid, notBefore)
+	}
+	issuedAt := claims.IssuedAt.AsTime()
+	if !issuedAt.IsZero() && issuedAt.After(now.Add(leeway)) {
+		return fmt.Errorf("%w: %w: iat %v", ErrInvalidToken, ErrTokenNotYetValid, issuedAt)
+	}
+	if j.validation.MaxAge > 0 {
+		if issuedAt.IsZero() {
+			return fmt.Errorf("%w: %w: iat", ErrInvalidToken, ErrMissingClaim)
+		}
+		if issuedAt.Before(now.Add(-j.validation.MaxAge - leeway)) {
+			return fmt.Errorf("%w: %w: iat %v", ErrInvalidToken, ErrTokenTooOld, issuedAt)
+		}
+	}
+
+	if len(j.validation.AuthorizedParties) > 0 {
+		if err := checkAuthorizedParty(claims, j.validation.AuthorizedParties); err != nil {
+			return err
+		}
+	}
+	if len(j.validation.RequiredClaims) > 0 {
+		if err := checkRequiredClaims(claims, j.validation.RequiredClaims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkAuthorizedParty(claims *oidc.AccessTokenClaims, allowed []string) error {
+	if claims.ClientID == "" && claims.AuthorizedParty == "" {
+		return fmt.Errorf("%w: %w: missing client_id and azp", ErrInvalidToken, ErrUnauthorizedParty)
+	}
+	for _, party := range []string{claims.ClientID, claims.AuthorizedParty} {
+		if party != "" && !slices.Contains(allowed, party) {
+			return fmt.Errorf("%w: %w: %s", ErrInvalidToken, ErrUnauthorizedParty, party)
+		}
+	}
+	return nil
+}
+
+func checkRequiredClaims(claims *oidc.AccessTokenClaims, required []string) error {
+	present := claims.Claims
+	missing := slices.ContainsFunc(required, func(claim string) bool {
+		_, ok := present[claim]
+		return !ok
+	})
+	if missing {
+		// standard claims are not part of the additional claims, so check the complete token
+		payload, err := json.Marshal(claims)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+		present = make(map[string]any)
+		if err = json.Unmarshal(payload, &present); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+	}
+	for _, claim := range required {
+		if _, ok := present[claim]; !ok {
+			return fmt.Errorf("%w: %w: %s", ErrInvalidToken, ErrMissingClaim, claim)
+		}
+	}
+	return nil
+}
+
+// verificationErr classifies the error of the signature, issuer and expiry verification.
+func verificationErr(err error) error {
+	switch {
+	case errors.Is(err, oidc.ErrExpired):
+		return fmt.Errorf("%w: %w: %w", ErrInvalidToken, ErrTokenExpired, err)
+	case errors.Is(err, oidc.ErrSignatureUnsupportedAlg):
+		return fmt.Errorf("%w: %w: %w", ErrInvalidToken, ErrUnsupportedAlgorithm, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+}
@@ -0,0 +1,220 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+func TestWithJWTValidation(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	z := zitadel.New("offline.invalid")
+
+	defaultParams := func() signParams {
+		return signParams{
+			KeyID:      key.KID(),
+			PrivateKey: key.Private(),
+			Issuer:     offlineIssuer,
+			Subject:    "user",
+			Audience:   []string{"test-client-id"},
+			TTL:        time.Hour,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options oauth.JWTValidationOptions
+		params  func(p *signParams)
+		wantErr error
+	}{
+		{
+			name:    "default audience",
+			options: oauth.JWTValidationOptions{},
+		},
+		{
+			name:    "default audience mismatch",
+			options: oauth.JWTValidationOptions{},
+			params: func(p *signParams) {
+				p.Audience = []string{"other"}
+			},
+			wantErr: oauth.ErrInvalidAudience,
+		},
+		{
+			name:    "one of audiences",
+			options: oauth.JWTValidationOptions{Audiences: []string{"api-1", "api-2"}},
+			params: func(p *signParams) {
+				p.Audience = []string{"other", "api-2"}
+			},
+		},
+		{
+			name:    "project id",
+			options: oauth.JWTValidationOptions{ProjectIDs: []string{"project"}},
+			params: func(p *signParams) {
+				p.Audience = []string{"client", "project"}
+			},
+		},
+		{
+			name:    "audiences replace client id",
+			options: oauth.JWTValidationOptions{ProjectIDs: []string{"project"}},
+			wantErr: oauth.ErrInvalidAudience,
+		},
+		{
+			name:    "algorithm not allowed",
+			options: oauth.JWTValidationOptions{SigningAlgorithms: []string{"ES256"}},
+			wantErr: oauth.ErrUnsupportedAlgorithm,
+		},
+		{
+			name:    "algorithm allowed",
+			options: oauth.JWTValidationOptions{SigningAlgorithms: []string{"RS512"}},
+			params: func(p *signParams) {
+				p.Algorithm = jwt.SigningMethodRS512
+			},
+		},
+		{
+			name:    "expired",
+			options: oauth.JWTValidationOptions{},
+			params: func(p *signParams) {
+				p.TTL = -time.Minute
+			},
+			wantErr: oauth.ErrTokenExpired,
+		},
+		{
+			name:    "expired within leeway",
+			options: oauth.JWTValidationOptions{Leeway: 2 * time.Minute},
+			params: func(p *signParams) {
+				p.TTL = -time.Minute
+			},
+		},
+		{
+			name:    "not yet valid",
+			options: oauth.JWTValidationOptions{},
+			params: func(p *signParams) {
+				p.NotBefore = time.Minute
+			},
+			wantErr: oauth.ErrTokenNotYetValid,
+		},
+		{
+			name:    "not yet valid within leeway",
+			options: oauth.JWTValidationOptions{Leeway: 2 * time.Minute},
+			params: func(p *signParams) {
+				p.NotBefore = time.Minute
+				p.IssuedAt = time.Minute
+			},
+		},
+		{
+			name:    "too old",
+			options: oauth.JWTValidationOptions{MaxAge: time.Hour},
+			params: func(p *signParams) {
+				p.IssuedAt = -2 * time.Hour
+				p.NotBefore = -2 * time.Hour
+			},
+			wantErr: oauth.ErrTokenTooOld,
+		},
+		{
+			name:    "required claims",
+			options: oauth.JWTValidationOptions{RequiredClaims: []string{"sub", "urn:zitadel:iam:user:resourceowner:id"}},
+			params: func(p *signParams) {
+				p.Claims = map[string]any{"urn:zitadel:iam:user:resourceowner:id": "org"}
+			},
+		},
+		{
+			name:    "required claim missing",
+			options: oauth.JWTValidationOptions{RequiredClaims: []string{"urn:zitadel:iam:user:resourceowner:id"}},
+			wantErr: oauth.ErrMissingClaim,
+		},
+		{
+			name:    "authorized party",
+			options: oauth.JWTValidationOptions{AuthorizedParties: []string{"app"}},
+			params: func(p *signParams) {
+				p.Claims = map[string]any{"client_id": "app", "azp": "app"}
+			},
+		},
+		{
+			name:    "authorized party mismatch",
+			options: oauth.JWTValidationOptions{AuthorizedParties: []string{"app"}},
+			params: func(p *signParams) {
+				p.Claims = map[string]any{"azp": "other"}
+			},
+			wantErr: oauth.ErrUnauthorizedParty,
+		},
+		{
+			name:    "authorized party missing",
+			options: oauth.JWTValidationOptions{AuthorizedParties: []string{"app"}},
+			wantErr: oauth.ErrUnauthorizedParty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := defaultParams()
+			if tt.params != nil {
+				tt.params(&params)
+			}
+			token, err := signTestJWT(params)
+			require.NoError(t, err)
+
+			verifier, err := oauth.WithJWTValidation(
+				oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromKeySet(testKeySet(key))),
+				tt.options,
+			)(context.Background(), z)
+			require.NoError(t, err)
+
+			_, err = verifier.CheckAuthorization(context.Background(), "Bearer "+token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, oauth.ErrInvalidToken)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestWithJWTValidation_wrapped verifies that the validation is applied through other wrappers, regardless of their order.
+func TestWithJWTValidation_wrapped(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	token, err := signTestJWT(signParams{
+		KeyID:      key.KID(),
+		PrivateKey: key.Private(),
+		Issuer:     offlineIssuer,
+		Subject:    "user",
+		Audience:   []string{"test-client-id"},
+		TTL:        time.Hour,
+	})
+	require.NoError(t, err)
+
+	verifier, err := oauth.WithJWTValidation(
+		authorization.WithCertificateBinding(
+			oauth.WithRevocation(oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromKeySet(testKeySet(key))), nil),
+		),
+		oauth.JWTValidationOptions{RequiredClaims: []string{"urn:zitadel:iam:user:resourceowner:id"}},
+	)(context.Background(), zitadel.New("offline.invalid"))
+	require.NoError(t, err)
+	_, err = verifier.CheckAuthorization(context.Background(), "Bearer "+token)
+	assert.ErrorIs(t, err, oauth.ErrMissingClaim)
+}
+
+func TestWithJWTValidation_unsupportedVerifier(t *testing.T) {
+	introspection := func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*oauth.IntrospectionContext], error) {
+		return new(introspectionVerifier), nil
+	}
+	_, err := authorization.New(context.Background(), zitadel.New("offline.invalid"),
+		oauth.WithJWTValidation(introspection, oauth.JWTValidationOptions{}),
+	)
+	assert.ErrorIs(t, err, oauth.ErrUnsupportedVerifier)
+}
+
+type introspectionVerifier struct{}
+
+func (*introspectionVerifier) CheckAuthorization(context.Context, string) (*oauth.IntrospectionContext, error) {
+	return nil, oauth.ErrIntrospectionFailed
+}
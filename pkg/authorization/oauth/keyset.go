@@ -0,0 +1,233 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"github.com/zitadel/oidc/v3/pkg/op"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+var (
+	ErrInvalidKeySet = errors.New("invalid key set")
+)
+
+// StaticKeySet is an [oidc.KeySet] verifying signatures with a fixed set of keys without any network access.
+// The keys can be replaced at runtime using [StaticKeySet.Update].
+type StaticKeySet struct {
+	mu   sync.RWMutex
+	keys []jose.JSONWebKey
+}
+
+var _ oidc.KeySet = (*StaticKeySet)(nil)
+
+// NewStaticKeySet creates a [StaticKeySet] with the provided keys.
+func NewStaticKeySet(keys jose.JSONWebKeySet) *StaticKeySet {
+	return &StaticKeySet{
+		keys: keys.Keys,
+	}
+}
+
+// ParseKeySet creates a [StaticKeySet] from a JSON encoded JWKS (e.g. the response of the ZITADEL `/oauth/v2/keys` endpoint).
+func ParseKeySet(data []byte) (*StaticKeySet, error) {
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticKeySet(keys), nil
+}
+
+// Update replaces the keys of the key set.
+func (s *StaticKeySet) Update(keys jose.JSONWebKeySet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys.Keys
+}
+
+// VerifySignature implements the [oidc.KeySet] interface by verifying the signature
+// with the key matching the `kid` and `alg` of the JWS header.
+func (s *StaticKeySet) VerifySignature(_ context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	keyID, alg := oidc.GetKeyIDAndAlg(jws)
+	s.mu.RLock()
+	key, err := oidc.FindMatchingKey(keyID, oidc.KeyUseSignature, alg, s.keys...)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find key: %w", err)
+	}
+	payload, err := jws.Verify(&key)
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+	return payload, nil
+}
+
+// KeySetLoader provides the [oidc.KeySet] for [WithOfflineJWT].
+// The context is the one passed to the [authorization.VerifierInitializer].
+type KeySetLoader func(ctx context.Context) (oidc.KeySet, error)
+
+// JWKSFromKeySet provides the keys of the passed [jose.JSONWebKeySet].
+func JWKSFromKeySet(keys jose.JSONWebKeySet) KeySetLoader {
+	return func(context.Context) (oidc.KeySet, error) {
+		return NewStaticKeySet(keys), nil
+	}
+}
+
+// JWKSFromBytes provides the keys of a JSON encoded JWKS.
+func JWKSFromBytes(data []byte) KeySetLoader {
+	return func(context.Context) (oidc.KeySet, error) {
+		return ParseKeySet(data)
+	}
+}
+
+// JWKSFromFile provides the keys of a JSON encoded JWKS file (see [NewFileKeySet]).
+// The reload of the file is stopped with the context of the initialization (see [WithOfflineJWT]).
+// To control the lifetime explicitly, create the [FileKeySet] and provide it with [FromKeySet] instead.
+func JWKSFromFile(path string, interval time.Duration) KeySetLoader {
+	return func(ctx context.Context) (oidc.KeySet, error) {
+		keySet, err := NewFileKeySet(path, interval)
+		if err != nil {
+			return nil, err
+		}
+		context.AfterFunc(ctx, keySet.Close)
+		return keySet, nil
+	}
+}
+
+// FromKeySet provides the passed [oidc.KeySet], e.g. a [FileKeySet] or [ManagedKeySet] created and closed by the caller.
+func FromKeySet(keySet oidc.KeySet) KeySetLoader {
+	return func(context.Context) (oidc.KeySet, error) {
+		return keySet, nil
+	}
+}
+
+// FileKeySet is a [StaticKeySet] with the keys of a JSON encoded JWKS file.
+// If created with an interval greater than 0, the file is checked for changes in the given interval
+// and the keys are reloaded until [FileKeySet.Close] is called.
+// If a changed file cannot be parsed, the previous keys are kept and a warning is logged (see [FileKeySet.SetLogger]).
+type FileKeySet struct {
+	*StaticKeySet
+	stop   context.CancelFunc
+	logger atomic.Pointer[slog.Logger]
+}
+
+// NewFileKeySet creates a [FileKeySet] by reading the JWKS file.
+// If interval is greater than 0, the file is reloaded on changes until [FileKeySet.Close] is called.
+func NewFileKeySet(path string, interval time.Duration) (*FileKeySet, error) {
+	data, modTime, err := readJWKSFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keySet, err := ParseKeySet(data)
+	if err != nil {
+		return nil, err
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	fileKeySet := &FileKeySet{
+		StaticKeySet: keySet,
+		stop:         stop,
+	}
+	fileKeySet.logger.Store(slog.Default())
+	if interval > 0 {
+		go fileKeySet.watch(ctx, path, interval, modTime)
+	}
+	return fileKeySet, nil
+}
+
+// Close stops the reload of the file. The current keys are kept.
+func (f *FileKeySet) Close() {
+	f.stop()
+}
+
+// SetLogger sets the logger for failed reloads of the file (default [slog.Default]).
+// It is called by [JWTVerification.SetLogger] with the logger of the [authorization.Authorizer] (see [authorization.WithLogger]).
+func (f *FileKeySet) SetLogger(logger *slog.Logger) {
+	f.logger.Store(logger)
+}
+
+// WithOfflineJWT creates the local JWT validation implementation of the [authorization.Verifier] interface
+// without any network access: Instead of the OIDC Discovery and the remote JWKS of [WithJWT],
+// the keys are provided by the [KeySetLoader] (e.g. [JWKSFromFile]) and the issuer is set explicitly.
+// If the issuer is empty, the origin of the [zitadel.Zitadel] is used.
+// Key sets refreshing their keys in the background (e.g. of [JWKSFromFile]) are stopped, when the context passed to the
+// [authorization.VerifierInitializer] is done. Therefore, the context must live as long as the verifier,
+// e.g. it must not be a timeout context for the startup.
+func WithOfflineJWT(clientID, issuer string, loader KeySetLoader, options ...op.AccessTokenVerifierOpt) authorization.VerifierInitializer[*IntrospectionContext] {
+	return func(ctx context.Context, zitadel *zitadel.Zitadel) (authorization.Verifier[*IntrospectionContext], error) {
+		keySet, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		iss := issuer
+		if iss == "" {
+			iss = zitadel.Origin()
+		}
+		return newJWTVerification(iss, keySet, clientID, options...), nil
+	}
+}
+
+func parseJWKS(data []byte) (keys jose.JSONWebKeySet, err error) {
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return keys, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+	if len(keys.Keys) == 0 {
+		return keys, fmt.Errorf("%w: no keys", ErrInvalidKeySet)
+	}
+	return keys, nil
+}
+
+func readJWKSFile(path string) ([]byte, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, info.ModTime(), nil
+}
+
+// watch reloads the keys of the key set, whenever the modification time of the file changes.
+func (f *FileKeySet) watch(ctx context.Context, path string, interval time.Duration, modTime time.Time) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			f.logger.Load().WarnContext(ctx, "unable to check jwks file", "path", path, "error", err)
+			continue
+		}
+		if info.ModTime().Equal(modTime) {
+			continue
+		}
+		data, changed, err := readJWKSFile(path)
+		if err != nil {
+			f.logger.Load().WarnContext(ctx, "unable to read jwks file", "path", path, "error", err)
+			continue
+		}
+		keys, err := parseJWKS(data)
+		if err != nil {
+			f.logger.Load().WarnContext(ctx, "unable to parse jwks file, keeping previous keys", "path", path, "error", err)
+			modTime = changed
+			continue
+		}
+		f.Update(keys)
+		modTime = changed
+	}
+}
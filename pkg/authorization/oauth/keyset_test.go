@@ -0,0 +1,248 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+const offlineIssuer = "https://offline.zitadel.cloud"
+
+func testKeySet(keys ...*TestKey) jose.JSONWebKeySet {
+	keySet := jose.JSONWebKeySet{}
+	for _, key := range keys {
+		keySet.Keys = append(keySet.Keys, jose.JSONWebKey{
+			Key:       key.Public(),
+			KeyID:     key.KID(),
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		})
+	}
+	return keySet
+}
+
+func offlineToken(t *testing.T, key *TestKey) string {
+	t.Helper()
+	token, err := signTestJWT(signParams{
+		KeyID:      key.KID(),
+		PrivateKey: key.Private(),
+		Issuer:     offlineIssuer,
+		Subject:    "user",
+		Audience:   []string{"test-client-id"},
+		TTL:        time.Hour,
+	})
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+func TestWithOfflineJWT(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	otherKey, err := NewTestKey(2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(testKeySet(key))
+	require.NoError(t, err)
+
+	// the domain is not resolvable, so any network access would fail
+	z := zitadel.New("offline.invalid")
+
+	tests := []struct {
+		name    string
+		loader  oauth.KeySetLoader
+		issuer  string
+		token   string
+		wantErr error
+	}{
+		{
+			name:   "key set",
+			loader: oauth.JWKSFromKeySet(testKeySet(key)),
+			issuer: offlineIssuer,
+			token:  offlineToken(t, key),
+		},
+		{
+			name:   "bytes",
+			loader: oauth.JWKSFromBytes(jwks),
+			issuer: offlineIssuer,
+			token:  offlineToken(t, key),
+		},
+		{
+			name:    "unknown key",
+			loader:  oauth.JWKSFromBytes(jwks),
+			issuer:  offlineIssuer,
+			token:   offlineToken(t, otherKey),
+			wantErr: oauth.ErrInvalidToken,
+		},
+		{
+			name:    "issuer mismatch",
+			loader:  oauth.JWKSFromBytes(jwks),
+			issuer:  "https://other.zitadel.cloud",
+			token:   offlineToken(t, key),
+			wantErr: oauth.ErrInvalidToken,
+		},
+		{
+			name:    "issuer defaults to origin",
+			loader:  oauth.JWKSFromBytes(jwks),
+			token:   offlineToken(t, key),
+			wantErr: oauth.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := oauth.WithOfflineJWT("test-client-id", tt.issuer, tt.loader)(context.Background(), z)
+			require.NoError(t, err)
+
+			authCtx, err := verifier.CheckAuthorization(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user", authCtx.UserID())
+		})
+	}
+}
+
+func TestWithOfflineJWT_invalidKeySet(t *testing.T) {
+	z := zitadel.New("offline.invalid")
+
+	_, err := authorization.New(context.Background(), z, oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromBytes([]byte("{}"))))
+	assert.ErrorIs(t, err, oauth.ErrInvalidKeySet)
+
+	_, err = authorization.New(context.Background(), z, oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromBytes([]byte("keys"))))
+	assert.ErrorIs(t, err, oauth.ErrInvalidKeySet)
+
+	_, err = authorization.New(context.Background(), z, oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromFile(filepath.Join(t.TempDir(), "missing.json"), 0)))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestJWKSFromFile_reload(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	rotatedKey, err := NewTestKey(2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeKeySet := func(data []byte, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	jwks, err := json.Marshal(testKeySet(key))
+	require.NoError(t, err)
+	writeKeySet(jwks, time.Now().Add(-time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	verifier, err := oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromFile(path, 10*time.Millisecond))(ctx, zitadel.New("offline.invalid"))
+	require.NoError(t, err)
+	logs := new(testLogHandler)
+	verifier.(authorization.LoggingVerifier).SetLogger(slog.New(logs))
+
+	_, err = verifier.CheckAuthorization(context.Background(), offlineToken(t, key))
+	require.NoError(t, err)
+	_, err = verifier.CheckAuthorization(context.Background(), offlineToken(t, rotatedKey))
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+
+	// an invalid file keeps the previous keys
+	writeKeySet([]byte("invalid"), time.Now().Add(-time.Minute))
+	require.Eventually(t, func() bool {
+		return logs.logged("unable to parse jwks file, keeping previous keys")
+	}, time.Second, 10*time.Millisecond)
+	_, err = verifier.CheckAuthorization(context.Background(), offlineToken(t, key))
+	require.NoError(t, err)
+
+	jwks, err = json.Marshal(testKeySet(key, rotatedKey))
+	require.NoError(t, err)
+	writeKeySet(jwks, time.Now())
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		_, err := verifier.CheckAuthorization(context.Background(), offlineToken(t, rotatedKey))
+		assert.NoError(c, err)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWithOfflineJWT_issuerPerInstance(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	initVerifier := oauth.WithOfflineJWT("test-client-id", "", oauth.JWKSFromKeySet(testKeySet(key)))
+
+	_, err = initVerifier(context.Background(), zitadel.New("other.zitadel.cloud"))
+	require.NoError(t, err)
+	// the issuer of a previous initialization must not be kept
+	verifier, err := initVerifier(context.Background(), zitadel.New("offline.zitadel.cloud"))
+	require.NoError(t, err)
+	_, err = verifier.CheckAuthorization(context.Background(), offlineToken(t, key))
+	assert.NoError(t, err)
+}
+
+func TestFileKeySet_Close(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	rotatedKey, err := NewTestKey(2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	jwks, err := json.Marshal(testKeySet(key))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, jwks, 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	keySet, err := oauth.NewFileKeySet(path, time.Millisecond)
+	require.NoError(t, err)
+	verifier, err := oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.FromKeySet(keySet))(context.Background(), zitadel.New("offline.invalid"))
+	require.NoError(t, err)
+	keySet.Close()
+
+	jwks, err = json.Marshal(testKeySet(rotatedKey))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, jwks, 0o600))
+	assert.Never(t, func() bool {
+		_, err := verifier.CheckAuthorization(context.Background(), offlineToken(t, rotatedKey))
+		return err == nil
+	}, 50*time.Millisecond, 5*time.Millisecond)
+	_, err = verifier.CheckAuthorization(context.Background(), offlineToken(t, key))
+	assert.NoError(t, err)
+}
+
+// testLogHandler is a [slog.Handler] recording the messages of the log records.
+type testLogHandler struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (h *testLogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *testLogHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, record.Message)
+	return nil
+}
+
+func (h *testLogHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *testLogHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func (h *testLogHandler) logged(message string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Contains(h.messages, message)
+}
@@ -0,0 +1,245 @@
+package oauth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultCacheShards is the default number of shards of the [LRUCache].
+	DefaultCacheShards = 16
+
+	// DefaultNegativeTTL is the default maximum TTL of inactive (unauthorized) tokens in the [LRUCache].
+	DefaultNegativeTTL = 10 * time.Second
+)
+
+var _ TokenCache[*IntrospectionContext] = (*LRUCache[*IntrospectionContext])(nil)
+
+// LRUCache is a bounded, in-memory implementation of the [TokenCache] interface.
+// It is safe for concurrent use and can be passed to [NewIntrospectionVerificationWithCache].
+//
+// Entries are distributed over multiple shards, each evicting the least recently used entry
+// when full. Tokens are used as keys by their SHA-256 hash. If the value provides a Clone method
+// (e.g. [IntrospectionContext.Clone]), it is copied on [LRUCache.Set] and [LRUCache.Get], so that callers
+// modifying the value (e.g. by [IntrospectionContext.SetToken]) neither race with each other nor store the raw token in the cache.
+// If the value provides its expiry (e.g. [IntrospectionContext.ExpiresAt]), the TTL is capped at the expiry of the token.
+// If the value reports to be unauthorized (e.g. an inactive [IntrospectionContext]), the TTL is capped at the negative TTL.
+type LRUCache[T any] struct {
+	shards      []*lruShard[T]
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+}
+
+// CacheStats provides the counters of the [LRUCache].
+type CacheStats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+	Entries     int
+}
+
+// LRUCacheOption allows customization of the [LRUCache].
+type LRUCacheOption func(*lruConfig)
+
+type lruConfig struct {
+	shards      int
+	negativeTTL time.Duration
+}
+
+// WithCacheShards sets the number of shards to reduce lock contention (default [DefaultCacheShards]).
+func WithCacheShards(shards int) LRUCacheOption {
+	return func(c *lruConfig) {
+		c.shards = shards
+	}
+}
+
+// WithNegativeTTL sets the maximum TTL for inactive tokens (default [DefaultNegativeTTL]).
+// A TTL of 0 disables caching of inactive tokens.
+func WithNegativeTTL(ttl time.Duration) LRUCacheOption {
+	return func(c *lruConfig) {
+		c.negativeTTL = ttl
+	}
+}
+
+// NewLRUCache creates an [LRUCache] holding at most maxEntries tokens.
+func NewLRUCache[T any](maxEntries int, options ...LRUCacheOption) *LRUCache[T] {
+	config := &lruConfig{
+		shards:      DefaultCacheShards,
+		negativeTTL: DefaultNegativeTTL,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	if config.shards < 1 {
+		config.shards = 1
+	}
+	if config.shards > maxEntries {
+		config.shards = maxEntries
+	}
+	capacity := (maxEntries + config.shards - 1) / config.shards
+	shards := make([]*lruShard[T], config.shards)
+	for i := range shards {
+		shards[i] = &lruShard[T]{
+			capacity: capacity,
+			entries:  make(map[[sha256.Size]byte]*list.Element, capacity),
+			order:    list.New(),
+		}
+	}
+	return &LRUCache[T]{
+		shards:      shards,
+		negativeTTL: config.negativeTTL,
+		now:         time.Now,
+	}
+}
+
+// Get implements the [TokenCache] interface and returns the cached value of the token if present and not expired.
+func (c *LRUCache[T]) Get(token string) (v T, ok bool) {
+	key := sha256.Sum256([]byte(token))
+	v, ok, expired := c.shard(key).get(key, c.now())
+	switch {
+	case ok:
+		c.hits.Add(1)
+	case expired:
+		c.expirations.Add(1)
+		c.misses.Add(1)
+	default:
+		c.misses.Add(1)
+	}
+	return cloneValue(v), ok
+}
+
+// Set implements the [TokenCache] interface and stores the value for the token.
+// The TTL is capped at the expiry of the token and for inactive tokens at the negative TTL.
+// Values with a TTL of 0 or less are not stored.
+func (c *LRUCache[T]) Set(token string, value T, ttl time.Duration) {
+	now := c.now()
+	if exp, ok := any(value).(interface{ ExpiresAt() time.Time }); ok {
+		if expiresAt := exp.ExpiresAt(); !expiresAt.IsZero() && expiresAt.Sub(now) < ttl {
+			ttl = expiresAt.Sub(now)
+		}
+	}
+	if active, ok := any(value).(interface{ IsAuthorized() bool }); ok && !active.IsAuthorized() && c.negativeTTL < ttl {
+		ttl = c.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	key := sha256.Sum256([]byte(token))
+	if c.shard(key).set(key, cloneValue(value), now.Add(ttl)) {
+		c.evictions.Add(1)
+	}
+}
+
+// Delete removes the token from the cache.
+func (c *LRUCache[T]) Delete(token string) {
+	key := sha256.Sum256([]byte(token))
+	c.shard(key).delete(key)
+}
+
+// Stats returns the current counters and the number of entries of the cache.
+func (c *LRUCache[T]) Stats() CacheStats {
+	entries := 0
+	for _, shard := range c.shards {
+		entries += shard.len()
+	}
+	return CacheStats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Entries:     entries,
+	}
+}
+
+func (c *LRUCache[T]) shard(key [sha256.Size]byte) *lruShard[T] {
+	return c.shards[binary.LittleEndian.Uint64(key[:8])%uint64(len(c.shards))]
+}
+
+// lruShard is a single LRU list protected by its own lock.
+type lruShard[T any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[[sha256.Size]byte]*list.Element
+	order    *list.List
+}
+
+type lruEntry[T any] struct {
+	key       [sha256.Size]byte
+	value     T
+	expiresAt time.Time
+}
+
+func (s *lruShard[T]) get(key [sha256.Size]byte, now time.Time) (v T, ok, expired bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return v, false, false
+	}
+	entry := element.Value.(*lruEntry[T])
+	if !now.Before(entry.expiresAt) {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		return v, false, true
+	}
+	s.order.MoveToFront(element)
+	return entry.value, true, false
+}
+
+// set stores the value and returns if another entry had to be evicted.
+func (s *lruShard[T]) set(key [sha256.Size]byte, value T, expiresAt time.Time) (evicted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*lruEntry[T])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(element)
+		return false
+	}
+	if s.order.Len() >= s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruEntry[T]).key)
+		evicted = true
+	}
+	s.entries[key] = s.order.PushFront(&lruEntry[T]{key: key, value: value, expiresAt: expiresAt})
+	return evicted
+}
+
+func (s *lruShard[T]) delete(key [sha256.Size]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+func (s *lruShard[T]) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// cloneValue returns a copy of the value if it provides a Clone method (e.g. [IntrospectionContext.Clone]),
+// so that a result shared between multiple callers can be modified independently.
+func cloneValue[T any](v T) T {
+	if c, ok := any(v).(interface{ Clone() T }); ok {
+		return c.Clone()
+	}
+	return v
+}
@@ -0,0 +1,158 @@
+package oauth
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+)
+
+func TestLRUCache_GetSet(t *testing.T) {
+	now := time.Now()
+	cache := NewLRUCache[string](10)
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.Get("token")
+	assert.False(t, ok)
+
+	cache.Set("token", "value", time.Minute)
+	v, ok := cache.Get("token")
+	assert.True(t, ok)
+	assert.Equal(t, "value", v)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("token")
+	assert.False(t, ok)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Expirations: 1}, cache.Stats())
+}
+
+func TestLRUCache_Clone(t *testing.T) {
+	cache := NewLRUCache[*IntrospectionContext](10)
+	value := &IntrospectionContext{IntrospectionResponse: oidc.IntrospectionResponse{Active: true}}
+	value.SetToken("token")
+	cache.Set("token", value, time.Minute)
+	assert.Equal(t, "token", value.GetToken())
+
+	first, ok := cache.Get("token")
+	assert.True(t, ok)
+	assert.Empty(t, first.GetToken())
+	first.SetToken("token")
+
+	second, ok := cache.Get("token")
+	assert.True(t, ok)
+	assert.NotSame(t, first, second)
+	assert.Empty(t, second.GetToken())
+}
+
+func TestLRUCache_Eviction(t *testing.T) {
+	cache := NewLRUCache[int](2, WithCacheShards(1))
+
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	// access a, so that b becomes the least recently used entry
+	_, _ = cache.Get("a")
+	cache.Set("c", 3, time.Minute)
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+func TestLRUCache_Delete(t *testing.T) {
+	cache := NewLRUCache[int](10)
+	cache.Set("a", 1, time.Minute)
+	cache.Delete("a")
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+}
+
+func TestLRUCache_TTL(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	tests := []struct {
+		name    string
+		value   *IntrospectionContext
+		ttl     time.Duration
+		wantTTL time.Duration
+	}{
+		{
+			name:    "active token without expiry",
+			value:   &IntrospectionContext{IntrospectionResponse: oidc.IntrospectionResponse{Active: true}},
+			ttl:     time.Minute,
+			wantTTL: time.Minute,
+		},
+		{
+			name: "active token, ttl capped at expiry",
+			value: &IntrospectionContext{IntrospectionResponse: oidc.IntrospectionResponse{
+				Active:     true,
+				Expiration: oidc.FromTime(now.Add(30 * time.Second)),
+			}},
+			ttl:     time.Minute,
+			wantTTL: 30 * time.Second,
+		},
+		{
+			name: "expired token is not cached",
+			value: &IntrospectionContext{IntrospectionResponse: oidc.IntrospectionResponse{
+				Active:     true,
+				Expiration: oidc.FromTime(now.Add(-time.Second)),
+			}},
+			ttl:     time.Minute,
+			wantTTL: 0,
+		},
+		{
+			name:    "inactive token, ttl capped at negative ttl",
+			value:   &IntrospectionContext{IntrospectionResponse: oidc.IntrospectionResponse{Active: false}},
+			ttl:     time.Minute,
+			wantTTL: 5 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := now
+			cache := NewLRUCache[*IntrospectionContext](10, WithNegativeTTL(5*time.Second))
+			cache.now = func() time.Time { return current }
+
+			cache.Set("token", tt.value, tt.ttl)
+			if tt.wantTTL == 0 {
+				assert.Equal(t, 0, cache.Stats().Entries)
+				return
+			}
+
+			current = now.Add(tt.wantTTL - time.Millisecond)
+			_, ok := cache.Get("token")
+			assert.True(t, ok, "entry should still be cached")
+
+			current = now.Add(tt.wantTTL)
+			_, ok = cache.Get("token")
+			assert.False(t, ok, "entry should be expired")
+		})
+	}
+}
+
+func TestLRUCache_Concurrent(t *testing.T) {
+	cache := NewLRUCache[int](100)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				token := fmt.Sprintf("token-%d-%d", i, j%200)
+				cache.Set(token, j, time.Minute)
+				_, _ = cache.Get(token)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, cache.Stats().Entries, 100+DefaultCacheShards)
+}
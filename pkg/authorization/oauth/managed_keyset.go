@@ -0,0 +1,450 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	gohttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/zitadel/oidc/v3/pkg/client"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"github.com/zitadel/oidc/v3/pkg/op"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+const (
+	// DefaultKeySetRefreshInterval is the default interval of the background refresh of the [ManagedKeySet],
+	// if the JWKS endpoint does not provide caching headers.
+	DefaultKeySetRefreshInterval = 15 * time.Minute
+
+	// DefaultKeySetMinRefreshInterval is the default minimum interval between two requests to the JWKS endpoint.
+	DefaultKeySetMinRefreshInterval = 10 * time.Second
+
+	// DefaultKeySetMaxRefreshInterval is the default maximum interval of the background refresh of the [ManagedKeySet],
+	// regardless of the caching headers.
+	DefaultKeySetMaxRefreshInterval = 24 * time.Hour
+
+	// keySetMinRefreshFloor is the lower bound of the minimum refresh interval,
+	// so the JWKS endpoint is never requested in a tight loop.
+	keySetMinRefreshFloor = time.Second
+)
+
+var _ oidc.KeySet = (*ManagedKeySet)(nil)
+
+// ManagedKeySet is an [oidc.KeySet] fetching the keys from a JWKS endpoint, designed for key rotations under load:
+//   - the keys are refreshed proactively in the background, honoring the Cache-Control (max-age) and Expires headers
+//   - tokens signed by an unknown key trigger a single (coalesced) refetch, rate-limited by the minimum refresh interval
+//   - if the JWKS endpoint fails, the last known good keys are kept
+//   - rotations and failures are reported through hooks (see [WithKeyRotationHook] and [WithKeySetErrorHook]) and [ManagedKeySet.Stats]
+//     and failures are logged (see [ManagedKeySet.SetLogger])
+type ManagedKeySet struct {
+	jwksURI string
+	config  *managedKeySetConfig
+	stop    context.CancelFunc
+	logger  atomic.Pointer[slog.Logger]
+
+	mu          sync.RWMutex
+	keys        []jose.JSONWebKey
+	lastFetch   time.Time
+	lastErr     error
+	fetches     uint64
+	lastRefresh time.Time
+	nextRefresh time.Time
+
+	// fetching allows a single request to the JWKS endpoint at a time
+	fetching chan struct{}
+
+	refreshes         atomic.Uint64
+	failures          atomic.Uint64
+	unknownKeyFetches atomic.Uint64
+	rateLimited       atomic.Uint64
+}
+
+// KeySetStats provides the counters of the [ManagedKeySet].
+type KeySetStats struct {
+	// Refreshes is the number of successful requests to the JWKS endpoint.
+	Refreshes uint64
+	// Failures is the number of failed requests to the JWKS endpoint.
+	Failures uint64
+	// UnknownKeyFetches is the number of requests triggered by tokens signed by an unknown key.
+	UnknownKeyFetches uint64
+	// RateLimited is the number of tokens signed by an unknown key, which did not trigger a request
+	// because of the minimum refresh interval.
+	RateLimited uint64
+	// Keys is the number of keys currently in use.
+	Keys int
+	// LastRefresh is the time of the last successful request to the JWKS endpoint.
+	LastRefresh time.Time
+	// NextRefresh is the time of the next scheduled background refresh.
+	NextRefresh time.Time
+}
+
+// KeyRotation describes the changes of the keys of the [ManagedKeySet] after a refresh.
+type KeyRotation struct {
+	Added   []string
+	Removed []string
+}
+
+// ManagedKeySetOption allows customization of the [ManagedKeySet].
+type ManagedKeySetOption func(*managedKeySetConfig)
+
+type managedKeySetConfig struct {
+	httpClient         *gohttp.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	maxRefreshInterval time.Duration
+	minRefreshFloor    time.Duration
+	onRotation         func(KeyRotation)
+	onError            func(error)
+	now                func() time.Time
+}
+
+// WithKeySetHTTPClient sets the HTTP client used for the requests to the JWKS endpoint (and the OIDC discovery of [WithManagedJWT]).
+func WithKeySetHTTPClient(httpClient *gohttp.Client) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithKeySetRefreshInterval sets the interval of the background refresh, if the JWKS endpoint does not provide caching headers
+// (default [DefaultKeySetRefreshInterval]).
+func WithKeySetRefreshInterval(interval time.Duration) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.refreshInterval = interval
+	}
+}
+
+// WithKeySetMinRefreshInterval sets the minimum interval between two requests to the JWKS endpoint
+// (default [DefaultKeySetMinRefreshInterval]). It rate-limits refetches on unknown keys
+// and is the initial retry interval if the JWKS endpoint fails.
+// Intervals below one second are raised to one second, so the JWKS endpoint is never requested in a tight loop.
+func WithKeySetMinRefreshInterval(interval time.Duration) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.minRefreshInterval = interval
+	}
+}
+
+// WithKeySetMaxRefreshInterval sets the maximum interval of the background refresh,
+// regardless of the caching headers (default [DefaultKeySetMaxRefreshInterval]).
+func WithKeySetMaxRefreshInterval(interval time.Duration) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.maxRefreshInterval = interval
+	}
+}
+
+// WithKeyRotationHook sets a function called whenever a refresh added or removed keys.
+// It is not called for the initial fetch.
+func WithKeyRotationHook(hook func(KeyRotation)) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.onRotation = hook
+	}
+}
+
+// WithKeySetErrorHook sets a function called whenever a request to the JWKS endpoint failed.
+func WithKeySetErrorHook(hook func(error)) ManagedKeySetOption {
+	return func(c *managedKeySetConfig) {
+		c.onError = hook
+	}
+}
+
+// NewManagedKeySet creates a [ManagedKeySet] and fetches the keys from the JWKS endpoint.
+// The context is only used for the initial fetch. The keys are refreshed in the background
+// until [ManagedKeySet.Close] is called.
+func NewManagedKeySet(ctx context.Context, jwksURI string, options ...ManagedKeySetOption) (*ManagedKeySet, error) {
+	config := newManagedKeySetConfig(options)
+	k := &ManagedKeySet{
+		jwksURI:  jwksURI,
+		config:   config,
+		fetching: make(chan struct{}, 1),
+	}
+	k.logger.Store(slog.Default())
+	k.fetching <- struct{}{}
+	err := k.refresh(ctx)
+	<-k.fetching
+	if err != nil {
+		return nil, err
+	}
+	runCtx, stop := context.WithCancel(context.Background())
+	k.stop = stop
+	go k.run(runCtx)
+	return k, nil
+}
+
+// Close stops the background refresh. The current keys are kept and refetched on unknown keys.
+func (k *ManagedKeySet) Close() {
+	k.stop()
+}
+
+// SetLogger sets the logger for failed requests to the JWKS endpoint (default [slog.Default]).
+// It is called by [JWTVerification.SetLogger] with the logger of the [authorization.Authorizer] (see [authorization.WithLogger]).
+func (k *ManagedKeySet) SetLogger(logger *slog.Logger) {
+	k.logger.Store(logger)
+}
+
+func newManagedKeySetConfig(options []ManagedKeySetOption) *managedKeySetConfig {
+	config := &managedKeySetConfig{
+		httpClient:         gohttp.DefaultClient,
+		refreshInterval:    DefaultKeySetRefreshInterval,
+		minRefreshInterval: DefaultKeySetMinRefreshInterval,
+		maxRefreshInterval: DefaultKeySetMaxRefreshInterval,
+		minRefreshFloor:    keySetMinRefreshFloor,
+		now:                time.Now,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	if config.httpClient == nil {
+		config.httpClient = gohttp.DefaultClient
+	}
+	config.minRefreshInterval = max(config.minRefreshInterval, config.minRefreshFloor)
+	return config
+}
+
+// WithManagedJWT creates the local JWT validation implementation of the [authorization.Verifier] interface
+// like [WithJWT], but uses a [ManagedKeySet] instead of the remote key set of [WithJWT] for the keys.
+// The background refresh is stopped with the context of the initialization like the reload of [JWKSFromFile] (see [WithOfflineJWT]).
+// To control the lifetime explicitly, create the [ManagedKeySet] and provide it to [WithOfflineJWT] using [FromKeySet] instead.
+func WithManagedJWT(clientID string, keySetOptions []ManagedKeySetOption, options ...op.AccessTokenVerifierOpt) authorization.VerifierInitializer[*IntrospectionContext] {
+	return func(ctx context.Context, zitadel *zitadel.Zitadel) (authorization.Verifier[*IntrospectionContext], error) {
+		config := newManagedKeySetConfig(keySetOptions)
+		discoveryConfig, err := client.Discover(ctx, zitadel.Origin(), config.httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+		}
+		keySet, err := NewManagedKeySet(ctx, discoveryConfig.JwksURI, keySetOptions...)
+		if err != nil {
+			return nil, err
+		}
+		context.AfterFunc(ctx, keySet.Close)
+		return newJWTVerification(discoveryConfig.Issuer, keySet, clientID, options...), nil
+	}
+}
+
+// VerifySignature implements the [oidc.KeySet] interface.
+// If no key matches the `kid` and `alg` of the JWS header, the keys are refetched (at most once per minimum refresh interval).
+// If the refetch fails, its error (e.g. an [authorization.HTTPStatusErr]) is returned.
+func (k *ManagedKeySet) VerifySignature(ctx context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	keyID, alg := oidc.GetKeyIDAndAlg(jws)
+	key, err := k.findKey(keyID, alg)
+	if err != nil {
+		if err = k.refetch(ctx); err != nil {
+			return nil, fmt.Errorf("unable to find key: %w", err)
+		}
+		key, err = k.findKey(keyID, alg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find key: %w", err)
+		}
+	}
+	payload, err := jws.Verify(&key)
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+	return payload, nil
+}
+
+// Stats returns the current counters of the key set.
+func (k *ManagedKeySet) Stats() KeySetStats {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return KeySetStats{
+		Refreshes:         k.refreshes.Load(),
+		Failures:          k.failures.Load(),
+		UnknownKeyFetches: k.unknownKeyFetches.Load(),
+		RateLimited:       k.rateLimited.Load(),
+		Keys:              len(k.keys),
+		LastRefresh:       k.lastRefresh,
+		NextRefresh:       k.nextRefresh,
+	}
+}
+
+func (k *ManagedKeySet) findKey(keyID, alg string) (jose.JSONWebKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return oidc.FindMatchingKey(keyID, oidc.KeyUseSignature, alg, k.keys...)
+}
+
+// refetch requests the keys because of an unknown key and returns the error of the request.
+// Concurrent callers wait for the same request instead of starting their own.
+func (k *ManagedKeySet) refetch(ctx context.Context) error {
+	k.mu.RLock()
+	fetches := k.fetches
+	k.mu.RUnlock()
+	select {
+	case k.fetching <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-k.fetching }()
+
+	k.mu.RLock()
+	lastFetch, lastErr, fetched := k.lastFetch, k.lastErr, k.fetches != fetches
+	k.mu.RUnlock()
+	if fetched {
+		// another caller fetched the keys while waiting
+		return lastErr
+	}
+	if k.config.now().Sub(lastFetch) < k.config.minRefreshInterval {
+		k.rateLimited.Add(1)
+		return nil
+	}
+	k.unknownKeyFetches.Add(1)
+	return k.refresh(ctx)
+}
+
+// run refreshes the keys in the background until the context is done.
+// If the JWKS endpoint fails, the refresh is retried with an exponential backoff.
+func (k *ManagedKeySet) run(ctx context.Context) {
+	retry := k.config.minRefreshInterval
+	for {
+		k.mu.RLock()
+		wait := k.nextRefresh.Sub(k.config.now())
+		k.mu.RUnlock()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		select {
+		case k.fetching <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		err := k.refresh(ctx)
+		<-k.fetching
+		if err == nil {
+			retry = k.config.minRefreshInterval
+			continue
+		}
+		k.mu.Lock()
+		k.nextRefresh = k.config.now().Add(retry)
+		k.mu.Unlock()
+		retry = min(retry*2, k.refreshInterval(-1))
+	}
+}
+
+// refresh fetches the keys and replaces them on success, otherwise the previous keys are kept.
+// The caller must hold the fetching semaphore.
+func (k *ManagedKeySet) refresh(ctx context.Context) error {
+	keys, maxAge, err := k.fetch(ctx)
+	now := k.config.now()
+	k.mu.Lock()
+	k.lastFetch = now
+	k.lastErr = err
+	k.fetches++
+	if err != nil {
+		k.mu.Unlock()
+		k.failures.Add(1)
+		k.logger.Load().WarnContext(ctx, "unable to refresh jwks, keeping previous keys", "jwks_uri", k.jwksURI, "error", err)
+		if k.config.onError != nil {
+			k.config.onError(err)
+		}
+		return err
+	}
+	initial := k.lastRefresh.IsZero()
+	rotation := keyRotation(k.keys, keys)
+	k.keys = keys
+	k.lastRefresh = now
+	k.nextRefresh = now.Add(k.refreshInterval(maxAge))
+	k.mu.Unlock()
+	k.refreshes.Add(1)
+
+	if k.config.onRotation != nil && !initial && (len(rotation.Added) > 0 || len(rotation.Removed) > 0) {
+		k.config.onRotation(rotation)
+	}
+	return nil
+}
+
+func (k *ManagedKeySet) fetch(ctx context.Context) (_ []jose.JSONWebKey, maxAge time.Duration, err error) {
+	req, err := gohttp.NewRequestWithContext(ctx, gohttp.MethodGet, k.jwksURI, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := k.config.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != gohttp.StatusOK {
+		return nil, 0, authorization.NewErrorHTTPStatus(resp.StatusCode, fmt.Errorf("unable to fetch jwks: %s", resp.Status))
+	}
+	var keySet jose.JSONWebKeySet
+	if err = json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+	if len(keySet.Keys) == 0 {
+		return nil, 0, fmt.Errorf("%w: no keys", ErrInvalidKeySet)
+	}
+	return keySet.Keys, cacheMaxAge(resp.Header, k.config.now()), nil
+}
+
+// refreshInterval returns the interval until the next background refresh,
+// based on the max age of the response (-1 if unknown) and bounded by the configured intervals.
+func (k *ManagedKeySet) refreshInterval(maxAge time.Duration) time.Duration {
+	interval := k.config.refreshInterval
+	if maxAge >= 0 {
+		interval = maxAge
+	}
+	return min(max(interval, k.config.minRefreshInterval), k.config.maxRefreshInterval)
+}
+
+// cacheMaxAge returns the max age of a response based on the Cache-Control and Expires headers,
+// or -1 if neither is present.
+func cacheMaxAge(header gohttp.Header, now time.Time) time.Duration {
+	if cacheControl := header.Get("Cache-Control"); cacheControl != "" {
+		for _, directive := range strings.Split(cacheControl, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			switch strings.ToLower(name) {
+			case "no-cache", "no-store":
+				return 0
+			case "max-age":
+				seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+				if err == nil && seconds >= 0 {
+					return time.Duration(seconds) * time.Second
+				}
+			}
+		}
+	}
+	if expires := header.Get("Expires"); expires != "" {
+		if t, err := gohttp.ParseTime(expires); err == nil {
+			return max(t.Sub(now), 0)
+		}
+	}
+	return -1
+}
+
+func keyRotation(previous, current []jose.JSONWebKey) KeyRotation {
+	keyIDs := func(keys []jose.JSONWebKey) []string {
+		ids := make([]string, len(keys))
+		for i, key := range keys {
+			ids[i] = key.KeyID
+		}
+		return ids
+	}
+	previousIDs, currentIDs := keyIDs(previous), keyIDs(current)
+	var rotation KeyRotation
+	for _, id := range currentIDs {
+		if !slices.Contains(previousIDs, id) {
+			rotation.Added = append(rotation.Added, id)
+		}
+	}
+	for _, id := range previousIDs {
+		if !slices.Contains(currentIDs, id) {
+			rotation.Removed = append(rotation.Removed, id)
+		}
+	}
+	return rotation
+}
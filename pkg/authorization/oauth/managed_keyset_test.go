@@ -0,0 +1,256 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+// jwksServer serves the discovery document and a JWKS, which can be changed during the test.
+type jwksServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	keys         jose.JSONWebKeySet
+	status       int
+	cacheControl string
+	requests     atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, keys jose.JSONWebKeySet) *jwksServer {
+	s := &jwksServer{keys: keys, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer":   "http://" + r.Host,
+				"jwks_uri": "http://" + r.Host + "/keys",
+			})
+		case "/keys":
+			s.requests.Add(1)
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.cacheControl != "" {
+				w.Header().Set("Cache-Control", s.cacheControl)
+			}
+			if s.status != http.StatusOK {
+				w.WriteHeader(s.status)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(s.keys)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(keys jose.JSONWebKeySet, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.status = status
+}
+
+func (s *jwksServer) token(t *testing.T, key *TestKey) string {
+	t.Helper()
+	token, err := signTestJWT(signParams{
+		KeyID:      key.KID(),
+		PrivateKey: key.Private(),
+		Issuer:     s.URL,
+		Subject:    "user",
+		Audience:   []string{"test-client-id"},
+		TTL:        time.Hour,
+	})
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+func (s *jwksServer) verifier(t *testing.T, ctx context.Context, options ...oauth.ManagedKeySetOption) authorization.Verifier[*oauth.IntrospectionContext] {
+	t.Helper()
+	parsedURL, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	z := zitadel.New(parsedURL.Hostname(), zitadel.WithInsecure(parsedURL.Port()))
+	verifier, err := oauth.WithManagedJWT("test-client-id", options)(ctx, z)
+	require.NoError(t, err)
+	return verifier
+}
+
+// testClock is a manually advanced clock for the [oauth.ManagedKeySet].
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Now()}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestManagedKeySet_unknownKey(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	rotatedKey, err := NewTestKey(2048)
+	require.NoError(t, err)
+	server := newJWKSServer(t, testKeySet(key))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	var rotations []oauth.KeyRotation
+	clock := newTestClock()
+	verifier := server.verifier(t, ctx,
+		oauth.WithKeySetClock(clock.Now),
+		oauth.WithKeySetMinRefreshFloor(0),
+		oauth.WithKeySetMinRefreshInterval(100*time.Millisecond),
+		oauth.WithKeyRotationHook(func(rotation oauth.KeyRotation) {
+			rotations = append(rotations, rotation)
+		}),
+	)
+	require.EqualValues(t, 1, server.requests.Load())
+
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, key))
+	require.NoError(t, err)
+
+	// a rotation within the minimum refresh interval is not fetched
+	server.set(testKeySet(rotatedKey), http.StatusOK)
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, rotatedKey))
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	assert.EqualValues(t, 1, server.requests.Load())
+
+	// concurrent requests with an unknown key result in a single fetch
+	clock.advance(150 * time.Millisecond)
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.CheckAuthorization(context.Background(), server.token(t, rotatedKey))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 2, server.requests.Load())
+	assert.Equal(t, []oauth.KeyRotation{{Added: []string{rotatedKey.KID()}, Removed: []string{key.KID()}}}, rotations)
+}
+
+func TestManagedKeySet_lastKnownGood(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	unknownKey, err := NewTestKey(2048)
+	require.NoError(t, err)
+	server := newJWKSServer(t, testKeySet(key))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	errs := make(chan error, 10)
+	clock := newTestClock()
+	verifier := server.verifier(t, ctx,
+		oauth.WithKeySetClock(clock.Now),
+		oauth.WithKeySetMinRefreshFloor(0),
+		oauth.WithKeySetMinRefreshInterval(time.Millisecond),
+		oauth.WithKeySetErrorHook(func(err error) {
+			errs <- err
+		}),
+	)
+	logs := new(testLogHandler)
+	verifier.(authorization.LoggingVerifier).SetLogger(slog.New(logs))
+
+	server.set(jose.JSONWebKeySet{}, http.StatusInternalServerError)
+	clock.advance(5 * time.Millisecond)
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, unknownKey))
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	// the failed fetch is reported, so the authorizer can respond with unavailable
+	require.ErrorIs(t, err, authorization.NewErrorHTTPStatus(http.StatusInternalServerError, nil))
+	err = <-errs
+	assert.ErrorIs(t, err, authorization.NewErrorHTTPStatus(http.StatusInternalServerError, nil))
+	assert.True(t, logs.logged("unable to refresh jwks, keeping previous keys"))
+
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, key))
+	assert.NoError(t, err)
+}
+
+func TestManagedKeySet_backgroundRefresh(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	rotatedKey, err := NewTestKey(2048)
+	require.NoError(t, err)
+	server := newJWKSServer(t, testKeySet(key))
+	server.cacheControl = "public, max-age=0"
+
+	rotated := make(chan oauth.KeyRotation, 1)
+	keySet, err := oauth.NewManagedKeySet(context.Background(), server.URL+"/keys",
+		oauth.WithKeySetMinRefreshFloor(0),
+		oauth.WithKeySetMinRefreshInterval(10*time.Millisecond),
+		oauth.WithKeyRotationHook(func(rotation oauth.KeyRotation) {
+			rotated <- rotation
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(keySet.Close)
+
+	server.set(testKeySet(key, rotatedKey), http.StatusOK)
+	select {
+	case rotation := <-rotated:
+		assert.Equal(t, oauth.KeyRotation{Added: []string{rotatedKey.KID()}}, rotation)
+	case <-time.After(time.Second):
+		t.Fatal("keys were not refreshed in the background")
+	}
+	stats := keySet.Stats()
+	assert.Equal(t, 2, stats.Keys)
+	assert.GreaterOrEqual(t, stats.Refreshes, uint64(2))
+	assert.Zero(t, stats.UnknownKeyFetches)
+}
+
+// TestManagedKeySet_minRefreshFloor verifies that the JWKS endpoint is not requested in a tight loop
+// even without a minimum refresh interval.
+func TestManagedKeySet_minRefreshFloor(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	server := newJWKSServer(t, testKeySet(key))
+	server.cacheControl = "no-cache"
+
+	clock := newTestClock()
+	keySet, err := oauth.NewManagedKeySet(context.Background(), server.URL+"/keys",
+		oauth.WithKeySetClock(clock.Now),
+		oauth.WithKeySetMinRefreshInterval(0),
+	)
+	require.NoError(t, err)
+	t.Cleanup(keySet.Close)
+	assert.Equal(t, clock.Now().Add(time.Second), keySet.Stats().NextRefresh)
+}
+
+func TestNewManagedKeySet_error(t *testing.T) {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	server := newJWKSServer(t, testKeySet(key))
+	server.set(jose.JSONWebKeySet{}, http.StatusOK)
+
+	_, err = oauth.NewManagedKeySet(context.Background(), server.URL+"/keys")
+	assert.ErrorIs(t, err, oauth.ErrInvalidKeySet)
+}
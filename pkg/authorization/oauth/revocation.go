@@ -0,0 +1,321 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+const (
+	// DefaultRevocationCheckInterval is the default interval in which a token (or session) is introspected by the [RevocationVerification].
+	DefaultRevocationCheckInterval = time.Minute
+
+	// DefaultRevocationTTL is the default time a revocation is kept, if no expiry is provided.
+	DefaultRevocationTTL = 12 * time.Hour
+
+	defaultRevocationCacheSize = 10000
+
+	// revocationSweepInterval is the minimum interval in which the [MemoryRevocationStore] removes expired revocations.
+	revocationSweepInterval = time.Minute
+)
+
+var (
+	ErrTokenRevoked = errors.New("token revoked")
+)
+
+// Revocation describes a revoked token (identified by its `jti`) or session (identified by its `sid`).
+type Revocation struct {
+	JWTID     string
+	SessionID string
+	// Until is the time the revocation can be removed, typically the expiry of the token.
+	// If not set, [DefaultRevocationTTL] is used.
+	Until time.Time
+}
+
+// RevocationStore is the denylist of revoked tokens and sessions used by the [RevocationVerification].
+// Implementations must be safe for concurrent use. Use [NewMemoryRevocationStore] for a single instance
+// or implement it with a shared store (e.g. Redis) to distribute revocations to multiple instances.
+type RevocationStore interface {
+	// Revoke adds the token and / or the session to the denylist.
+	Revoke(ctx context.Context, revocation Revocation) error
+	// IsRevoked returns if the token or the session was revoked.
+	// Any of the ids might be empty and must not match in that case.
+	IsRevoked(ctx context.Context, jwtID, sessionID string) (bool, error)
+}
+
+// MemoryRevocationStore is an in-memory implementation of the [RevocationStore] interface.
+type MemoryRevocationStore struct {
+	mu        sync.RWMutex
+	tokens    map[string]time.Time
+	sessions  map[string]time.Time
+	nextSweep time.Time
+}
+
+var _ RevocationStore = (*MemoryRevocationStore)(nil)
+
+// NewMemoryRevocationStore creates an empty [MemoryRevocationStore].
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens:   make(map[string]time.Time),
+		sessions: make(map[string]time.Time),
+	}
+}
+
+// Revoke implements the [RevocationStore] interface.
+// Expired revocations are ignored and removed at most once per minute.
+func (s *MemoryRevocationStore) Revoke(_ context.Context, revocation Revocation) error {
+	now := time.Now()
+	until := revocation.Until
+	if until.IsZero() {
+		until = now.Add(DefaultRevocationTTL)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.After(s.nextSweep) {
+		s.sweep(now)
+	}
+	if revocation.JWTID != "" {
+		s.tokens[revocation.JWTID] = until
+	}
+	if revocation.SessionID != "" {
+		s.sessions[revocation.SessionID] = until
+	}
+	return nil
+}
+
+func (s *MemoryRevocationStore) sweep(now time.Time) {
+	for _, revoked := range []map[string]time.Time{s.tokens, s.sessions} {
+		for id, expiry := range revoked {
+			if now.After(expiry) {
+				delete(revoked, id)
+			}
+		}
+	}
+	s.nextSweep = now.Add(revocationSweepInterval)
+}
+
+// IsRevoked implements the [RevocationStore] interface.
+func (s *MemoryRevocationStore) IsRevoked(_ context.Context, jwtID, sessionID string) (bool, error) {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if until, ok := s.tokens[jwtID]; ok && jwtID != "" && !now.After(until) {
+		return true, nil
+	}
+	if until, ok := s.sessions[sessionID]; ok && sessionID != "" && !now.After(until) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// RevocationVerification provides an [authorization.Verifier] implementation, which adds a revocation check
+// to a locally validating verifier (e.g. [WithJWT]), without introspecting every request:
+//   - every token is checked against the [RevocationStore], which can be filled from outside
+//     (e.g. by a webhook handler using [RevocationStore.Revoke])
+//   - every session (`sid`), or token (`jti`) if there is no session, is introspected at most once per check interval
+//   - additionally a sample of all requests can be introspected
+//
+// If the introspection reports the token as inactive, the token (`jti`) is added to the [RevocationStore].
+// Since an inactive token does not imply a terminated session (e.g. it might be issued for another audience),
+// sessions are only revoked through the [RevocationStore].
+// If the introspection fails, the request is accepted and the token is introspected again on the next request,
+// unless [WithRevocationFailClosed] is set.
+// Use [WithRevocation] for implementation.
+type RevocationVerification struct {
+	verifier      authorization.Verifier[*IntrospectionContext]
+	introspection authorization.Verifier[*IntrospectionContext]
+	store         RevocationStore
+	interval      time.Duration
+	sampleRate    float64
+	failClosed    bool
+	checked       *LRUCache[struct{}]
+	logger        *slog.Logger
+}
+
+var (
+	_ authorization.LoggingVerifier                         = (*RevocationVerification)(nil)
+	_ authorization.WrappingVerifier[*IntrospectionContext] = (*RevocationVerification)(nil)
+)
+
+// RevocationOption allows customization of the [RevocationVerification].
+type RevocationOption func(*revocationConfig)
+
+type revocationConfig struct {
+	store      RevocationStore
+	interval   time.Duration
+	sampleRate float64
+	failClosed bool
+	cacheSize  int
+	now        func() time.Time
+}
+
+// WithRevocationStore sets the [RevocationStore] (default: a new [MemoryRevocationStore]).
+// Pass the same store to your webhook handler to push revocations.
+func WithRevocationStore(store RevocationStore) RevocationOption {
+	return func(c *revocationConfig) {
+		c.store = store
+	}
+}
+
+// WithRevocationCheckInterval sets the interval in which a session or token is introspected
+// (default [DefaultRevocationCheckInterval]). An interval of 0 disables the periodic introspection.
+func WithRevocationCheckInterval(interval time.Duration) RevocationOption {
+	return func(c *revocationConfig) {
+		c.interval = interval
+	}
+}
+
+// WithRevocationSampleRate additionally introspects the given fraction (between 0 and 1) of all requests.
+func WithRevocationSampleRate(rate float64) RevocationOption {
+	return func(c *revocationConfig) {
+		c.sampleRate = rate
+	}
+}
+
+// WithRevocationFailClosed rejects requests, which cannot be checked because the introspection failed.
+// By default, such requests are accepted (fail-open).
+func WithRevocationFailClosed() RevocationOption {
+	return func(c *revocationConfig) {
+		c.failClosed = true
+	}
+}
+
+// WithRevocationCacheSize sets the maximum number of sessions and tokens remembered as recently introspected.
+func WithRevocationCacheSize(size int) RevocationOption {
+	return func(c *revocationConfig) {
+		c.cacheSize = size
+	}
+}
+
+// WithRevocation creates the [RevocationVerification] implementation of the [authorization.Verifier] interface
+// around the verifier created by the initializer (e.g. [WithJWT]).
+// The introspection endpoint requires some [IntrospectionAuthentication] of the client.
+// If auth is nil, tokens are only checked against the [RevocationStore].
+func WithRevocation(initializer authorization.VerifierInitializer[*IntrospectionContext], auth IntrospectionAuthentication, options ...RevocationOption) authorization.VerifierInitializer[*IntrospectionContext] {
+	config := &revocationConfig{
+		interval:  DefaultRevocationCheckInterval,
+		cacheSize: defaultRevocationCacheSize,
+		now:       time.Now,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	if config.store == nil {
+		config.store = NewMemoryRevocationStore()
+	}
+	return func(ctx context.Context, zitadel *zitadel.Zitadel) (authorization.Verifier[*IntrospectionContext], error) {
+		verifier, err := initializer(ctx, zitadel)
+		if err != nil {
+			return nil, err
+		}
+		verification := &RevocationVerification{
+			verifier: verifier,
+			store:    config.store,
+			logger:   slog.Default(),
+		}
+		if auth == nil {
+			return verification, nil
+		}
+		verification.introspection, err = WithIntrospection[*IntrospectionContext](auth, WithRequestCoalescing())(ctx, zitadel)
+		if err != nil {
+			return nil, err
+		}
+		verification.interval = config.interval
+		verification.sampleRate = config.sampleRate
+		verification.failClosed = config.failClosed
+		verification.checked = NewLRUCache[struct{}](config.cacheSize)
+		verification.checked.now = config.now
+		return verification, nil
+	}
+}
+
+// CheckAuthorization implements the [authorization.Verifier] interface.
+// The token is verified by the wrapped verifier first and then checked for revocation.
+func (r *RevocationVerification) CheckAuthorization(ctx context.Context, authorizationToken string) (*IntrospectionContext, error) {
+	resp, err := r.verifier.CheckAuthorization(ctx, authorizationToken)
+	if err != nil || !resp.IsAuthorized() {
+		return resp, err
+	}
+	jwtID, sessionID := resp.JWTID, resp.SessionID()
+	revoked, err := r.store.IsRevoked(ctx, jwtID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check revocation: %w", err)
+	}
+	if revoked {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrTokenRevoked)
+	}
+	if r.introspection == nil {
+		return resp, nil
+	}
+
+	key := revocationCheckKey(jwtID, sessionID, authorizationToken)
+	if !r.shouldIntrospect(key) {
+		return resp, nil
+	}
+	introspected, err := r.introspection.CheckAuthorization(ctx, authorizationToken)
+	if err != nil {
+		if r.failClosed {
+			return nil, fmt.Errorf("unable to check revocation: %w", err)
+		}
+		r.logger.WarnContext(ctx, "unable to introspect token for revocation check", "error", err)
+		return resp, nil
+	}
+	if introspected.IsAuthorized() {
+		r.checked.Set(key, struct{}{}, r.interval)
+		return resp, nil
+	}
+	if jwtID != "" {
+		err = r.store.Revoke(ctx, Revocation{
+			JWTID: jwtID,
+			Until: resp.ExpiresAt(),
+		})
+		if err != nil {
+			r.logger.WarnContext(ctx, "unable to store revocation", "error", err)
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrTokenRevoked)
+}
+
+// Unwrap implements the [authorization.WrappingVerifier] interface by returning the locally validating verifier.
+func (r *RevocationVerification) Unwrap() authorization.Verifier[*IntrospectionContext] {
+	return r.verifier
+}
+
+// SetLogger implements the [authorization.LoggingVerifier] interface.
+func (r *RevocationVerification) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+func (r *RevocationVerification) shouldIntrospect(key string) bool {
+	if r.sampleRate > 0 && rand.Float64() < r.sampleRate {
+		return true
+	}
+	if r.interval <= 0 {
+		return false
+	}
+	_, checked := r.checked.Get(key)
+	return !checked
+}
+
+// revocationCheckKey returns the key the periodic introspection is tracked by:
+// the session if present, so all tokens of a session share the check, otherwise the token itself.
+func revocationCheckKey(jwtID, sessionID, authorizationToken string) string {
+	switch {
+	case sessionID != "":
+		return "sid:" + sessionID
+	case jwtID != "":
+		return "jti:" + jwtID
+	default:
+		return strings.TrimSpace(strings.TrimPrefix(authorizationToken, oidc.BearerToken))
+	}
+}
@@ -0,0 +1,203 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization/oauth"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+// revocationServer provides the discovery and an introspection endpoint,
+// reporting tokens of terminated sessions as inactive.
+type revocationServer struct {
+	*httptest.Server
+	key            *TestKey
+	introspections atomic.Int32
+	unavailable    atomic.Bool
+	mu             sync.Mutex
+	terminated     map[string]bool
+}
+
+func newRevocationServer(t *testing.T) *revocationServer {
+	key, err := NewTestKey(2048)
+	require.NoError(t, err)
+	s := &revocationServer{key: key, terminated: make(map[string]bool)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"issuer":                 "http://" + r.Host,
+				"token_endpoint":         "http://" + r.Host + "/token",
+				"introspection_endpoint": "http://" + r.Host + "/introspect",
+			})
+		case "/introspect":
+			s.introspections.Add(1)
+			if s.unavailable.Load() {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			var claims struct {
+				SessionID string `json:"sid"`
+			}
+			parts := strings.Split(r.FormValue("token"), ".")
+			payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+			_ = json.Unmarshal(payload, &claims)
+			s.mu.Lock()
+			active := !s.terminated[claims.SessionID]
+			s.mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"active": active, "sub": "user"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *revocationServer) terminate(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.terminated[sessionID] = true
+}
+
+func (s *revocationServer) token(t *testing.T, jwtID, sessionID string) string {
+	t.Helper()
+	token, err := signTestJWT(signParams{
+		KeyID:      s.key.KID(),
+		PrivateKey: s.key.Private(),
+		Issuer:     offlineIssuer,
+		Subject:    "user",
+		Audience:   []string{"test-client-id"},
+		TTL:        time.Hour,
+		Claims:     map[string]any{"jti": jwtID, "sid": sessionID},
+	})
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+func (s *revocationServer) verifier(t *testing.T, auth oauth.IntrospectionAuthentication, options ...oauth.RevocationOption) authorization.Verifier[*oauth.IntrospectionContext] {
+	t.Helper()
+	parsedURL, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	z := zitadel.New(parsedURL.Hostname(), zitadel.WithInsecure(parsedURL.Port()))
+	verifier, err := oauth.WithRevocation(
+		oauth.WithOfflineJWT("test-client-id", offlineIssuer, oauth.JWKSFromKeySet(testKeySet(s.key))),
+		auth,
+		options...,
+	)(context.Background(), z)
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestWithRevocation_store(t *testing.T) {
+	server := newRevocationServer(t)
+	store := oauth.NewMemoryRevocationStore()
+	verifier := server.verifier(t, nil, oauth.WithRevocationStore(store))
+
+	_, err := verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	require.NoError(t, err)
+
+	// pushed revocations are rejected immediately
+	require.NoError(t, store.Revoke(context.Background(), oauth.Revocation{SessionID: "session-1"}))
+	require.NoError(t, store.Revoke(context.Background(), oauth.Revocation{JWTID: "token-2", Until: time.Now().Add(time.Hour)}))
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	assert.ErrorIs(t, err, oauth.ErrTokenRevoked)
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-2", "session-2"))
+	assert.ErrorIs(t, err, oauth.ErrTokenRevoked)
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-3", "session-2"))
+	assert.NoError(t, err)
+
+	// expired revocations are ignored
+	require.NoError(t, store.Revoke(context.Background(), oauth.Revocation{SessionID: "session-3", Until: time.Now().Add(-time.Second)}))
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-4", "session-3"))
+	assert.NoError(t, err)
+	assert.Zero(t, server.introspections.Load())
+}
+
+func TestWithRevocation_interval(t *testing.T) {
+	server := newRevocationServer(t)
+	auth := oauth.ClientIDSecretIntrospectionAuthentication("client", "secret")
+	clock := newTestClock()
+	verifier := server.verifier(t, auth, oauth.WithRevocationCheckInterval(time.Minute), oauth.WithRevocationClock(clock.Now))
+
+	// the session is introspected once per interval, regardless of the token
+	_, err := verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	require.NoError(t, err)
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-2", "session-1"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, server.introspections.Load())
+
+	server.terminate("session-1")
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	require.NoError(t, err)
+
+	clock.advance(time.Minute + time.Second)
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	assert.ErrorIs(t, err, oauth.ErrTokenRevoked)
+	assert.EqualValues(t, 2, server.introspections.Load())
+
+	// only the inactive token is remembered, other tokens of the session are introspected on their own
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	assert.ErrorIs(t, err, oauth.ErrTokenRevoked)
+	assert.EqualValues(t, 2, server.introspections.Load())
+	_, err = verifier.CheckAuthorization(context.Background(), server.token(t, "token-3", "session-1"))
+	assert.ErrorIs(t, err, oauth.ErrTokenRevoked)
+	assert.EqualValues(t, 3, server.introspections.Load())
+}
+
+func TestWithRevocation_inactiveTokenKeepsSession(t *testing.T) {
+	server := newRevocationServer(t)
+	store := oauth.NewMemoryRevocationStore()
+	auth := oauth.ClientIDSecretIntrospectionAuthentication("client", "secret")
+	verifier := server.verifier(t, auth, oauth.WithRevocationStore(store))
+
+	server.terminate("session-1")
+	_, err := verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	assert.ErrorIs(t, err, oauth.ErrTokenRevoked)
+
+	revoked, err := store.IsRevoked(context.Background(), "token-1", "")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = store.IsRevoked(context.Background(), "", "session-1")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestWithRevocation_introspectionFails(t *testing.T) {
+	server := newRevocationServer(t)
+	server.unavailable.Store(true)
+	auth := oauth.ClientIDSecretIntrospectionAuthentication("client", "secret")
+
+	// fail-open by default
+	_, err := server.verifier(t, auth).CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	assert.NoError(t, err)
+
+	_, err = server.verifier(t, auth, oauth.WithRevocationFailClosed()).CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+	assert.ErrorIs(t, err, authorization.NewErrorHTTPStatus(http.StatusServiceUnavailable, nil))
+}
+
+func TestWithRevocation_sampled(t *testing.T) {
+	server := newRevocationServer(t)
+	auth := oauth.ClientIDSecretIntrospectionAuthentication("client", "secret")
+	verifier := server.verifier(t, auth, oauth.WithRevocationCheckInterval(0), oauth.WithRevocationSampleRate(1))
+
+	for range 3 {
+		_, err := verifier.CheckAuthorization(context.Background(), server.token(t, "token-1", "session-1"))
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 3, server.introspections.Load())
+}
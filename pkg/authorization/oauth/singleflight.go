@@ -0,0 +1,137 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// DefaultCoalescingTimeout is the default timeout of a coalesced introspection call (see [WithRequestCoalescing]),
+// if the context of the first caller does not have a deadline.
+const DefaultCoalescingTimeout = 10 * time.Second
+
+// IntrospectionOption allows customization of the introspection verifiers
+// ([WithIntrospection] and [NewIntrospectionVerificationWithCache]).
+type IntrospectionOption func(*introspectionConfig)
+
+type introspectionConfig struct {
+	coalesce        bool
+	coalesceTimeout time.Duration
+}
+
+// WithRequestCoalescing de-duplicates concurrent introspection calls for the same token:
+// Only the first caller calls the introspection endpoint, concurrent callers wait for and share its result.
+// Each caller can still abort waiting by canceling its own context. The shared call is only canceled
+// once all callers have canceled or once the deadline of the first caller is exceeded.
+// If the first caller has no deadline, the call is limited by [DefaultCoalescingTimeout] (see [WithCoalescingTimeout]).
+func WithRequestCoalescing() IntrospectionOption {
+	return func(c *introspectionConfig) {
+		c.coalesce = true
+	}
+}
+
+// WithCoalescingTimeout sets the timeout of a coalesced introspection call, if the first caller has no deadline
+// (default [DefaultCoalescingTimeout]). It implies [WithRequestCoalescing].
+func WithCoalescingTimeout(timeout time.Duration) IntrospectionOption {
+	return func(c *introspectionConfig) {
+		c.coalesce = true
+		c.coalesceTimeout = timeout
+	}
+}
+
+func newIntrospectionConfig(options []IntrospectionOption) *introspectionConfig {
+	config := &introspectionConfig{
+		coalesceTimeout: DefaultCoalescingTimeout,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// flightGroup de-duplicates concurrent calls for the same token.
+type flightGroup[T any] struct {
+	mu      sync.Mutex
+	calls   map[[sha256.Size]byte]*flightCall[T]
+	timeout time.Duration
+}
+
+type flightCall[T any] struct {
+	done    chan struct{}
+	resp    T
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup[T any](timeout time.Duration) *flightGroup[T] {
+	return &flightGroup[T]{
+		calls:   make(map[[sha256.Size]byte]*flightCall[T]),
+		timeout: timeout,
+	}
+}
+
+// do calls fn for the token unless a call for the same token is already in flight,
+// in which case it waits for its result. If ctx is canceled while waiting, ctx.Err() is returned.
+func (g *flightGroup[T]) do(ctx context.Context, token string, fn func(ctx context.Context) (T, error)) (resp T, err error) {
+	key := sha256.Sum256([]byte(token))
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := g.callContext(ctx)
+		call = &flightCall[T]{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = call
+		go g.run(callCtx, key, call, fn)
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return cloneValue(call.resp), call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// no caller is interested in the result anymore, new callers must start a new call
+			call.cancel()
+			g.forget(key, call)
+		}
+		g.mu.Unlock()
+		return resp, ctx.Err()
+	}
+}
+
+// callContext returns the context of a shared call: It is not canceled with the context of the first caller,
+// but keeps its deadline or, if there is none, is limited by the timeout of the group.
+func (g *flightGroup[T]) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	callCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(callCtx, deadline)
+	}
+	if g.timeout > 0 {
+		return context.WithTimeout(callCtx, g.timeout)
+	}
+	return context.WithCancel(callCtx)
+}
+
+func (g *flightGroup[T]) run(ctx context.Context, key [sha256.Size]byte, call *flightCall[T], fn func(ctx context.Context) (T, error)) {
+	defer call.cancel()
+	call.resp, call.err = fn(ctx)
+	g.mu.Lock()
+	g.forget(key, call)
+	g.mu.Unlock()
+	close(call.done)
+}
+
+// forget removes the call from the in-flight calls, if it was not already replaced by a new one.
+// The caller must hold the lock.
+func (g *flightGroup[T]) forget(key [sha256.Size]byte, call *flightCall[T]) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
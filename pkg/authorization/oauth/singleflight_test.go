@@ -0,0 +1,163 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospectionVerification_RequestCoalescing(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{})}
+	verification := &IntrospectionVerification[*IntrospectionContext]{
+		ResourceServer: &resourceServer{client: &http.Client{Transport: transport}},
+		flight:         newFlightGroup[*IntrospectionContext](0),
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]*IntrospectionContext, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := verification.CheckAuthorization(context.Background(), "Bearer token")
+			assert.NoError(t, err)
+			results[i] = resp
+		}(i)
+	}
+	require.Eventually(t, func() bool {
+		verification.flight.mu.Lock()
+		defer verification.flight.mu.Unlock()
+		call, ok := verification.flight.calls[sha256Sum("token")]
+		return ok && call.waiters == callers
+	}, time.Second, time.Millisecond)
+	close(transport.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), transport.calls.Load())
+	for _, resp := range results {
+		require.NotNil(t, resp)
+		assert.Equal(t, "sub", resp.Subject)
+	}
+	// every caller must receive its own copy
+	assert.NotSame(t, results[0], results[1])
+}
+
+func TestIntrospectionVerificationWithCache_RequestCoalescing(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{})}
+	close(transport.release)
+	verification := NewIntrospectionVerificationWithCache[*IntrospectionContext](
+		&resourceServer{client: &http.Client{Transport: transport}},
+		NewLRUCache[*IntrospectionContext](10),
+		time.Minute,
+		WithRequestCoalescing(),
+	)
+	for i := 0; i < 3; i++ {
+		resp, err := verification.CheckAuthorization(context.Background(), "Bearer token")
+		require.NoError(t, err)
+		assert.Equal(t, "sub", resp.Subject)
+	}
+	assert.Equal(t, int32(1), transport.calls.Load())
+}
+
+func TestFlightGroup_Cancellation(t *testing.T) {
+	group := newFlightGroup[string](0)
+	started := make(chan struct{})
+	callCanceled := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		close(started)
+		<-ctx.Done()
+		close(callCanceled)
+		return "", ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() {
+		_, err := group.do(ctx1, "token", fn)
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, err := group.do(ctx2, "token", fn)
+		errs <- err
+	}()
+	require.Eventually(t, func() bool {
+		group.mu.Lock()
+		defer group.mu.Unlock()
+		return group.calls[sha256Sum("token")].waiters == 2
+	}, time.Second, time.Millisecond)
+
+	// canceling the first caller must not cancel the shared call
+	cancel1()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	select {
+	case <-callCanceled:
+		t.Fatal("shared call must not be canceled while another caller is waiting")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// once all callers canceled, the shared call is canceled as well
+	cancel2()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	select {
+	case <-callCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("shared call must be canceled once all callers canceled")
+	}
+}
+
+func TestFlightGroup_Deadline(t *testing.T) {
+	var deadline time.Time
+	fn := func(ctx context.Context) (string, error) {
+		deadline, _ = ctx.Deadline()
+		return "", nil
+	}
+
+	t.Run("deadline of the first caller", func(t *testing.T) {
+		want := time.Now().Add(time.Minute)
+		ctx, cancel := context.WithDeadline(context.Background(), want)
+		defer cancel()
+		_, err := newFlightGroup[string](time.Hour).do(ctx, "token", fn)
+		require.NoError(t, err)
+		assert.Equal(t, want, deadline)
+	})
+	t.Run("timeout without deadline", func(t *testing.T) {
+		_, err := newFlightGroup[string](time.Minute).do(context.Background(), "token", fn)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	})
+}
+
+func sha256Sum(token string) [32]byte {
+	return sha256.Sum256([]byte(token))
+}
+
+// blockingTransport returns an active introspection response once released and counts the calls.
+type blockingTransport struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.calls.Add(1)
+	select {
+	case <-b.release:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"active": true, "sub": "sub"}`))),
+	}, nil
+}
@@ -0,0 +1,146 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/zitadel/oidc/v3/pkg/client/rp"
+	"github.com/zitadel/oidc/v3/pkg/client/rs"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+type statusKey struct{}
+
+// introspect calls the introspection endpoint and returns typed errors:
+// If the endpoint responded with a non-successful status, an [authorization.HTTPStatusErr] is returned.
+// Network failures and context errors are kept in the error chain, so they can be matched with [errors.As] and [errors.Is].
+func introspect[T any](ctx context.Context, resourceServer rs.ResourceServer, token string) (resp T, err error) {
+	statusCode := new(int)
+	ctx = context.WithValue(ctx, statusKey{}, statusCode)
+	resp, err = rs.Introspect[T](ctx, &statusResourceServer{ResourceServer: resourceServer}, token)
+	if err == nil {
+		return resp, nil
+	}
+	err = fmt.Errorf("%w: %w", ErrIntrospectionFailed, err)
+	if *statusCode != 0 && *statusCode != http.StatusOK {
+		return resp, authorization.NewErrorHTTPStatus(*statusCode, err)
+	}
+	return resp, err
+}
+
+// statusResourceServer wraps the [rs.ResourceServer] to record the HTTP status code of the introspection response.
+type statusResourceServer struct {
+	rs.ResourceServer
+}
+
+func (s *statusResourceServer) HttpClient() *http.Client {
+	client := http.DefaultClient
+	if c := s.ResourceServer.HttpClient(); c != nil {
+		client = c
+	}
+	recording := *client
+	recording.Transport = &statusTransport{base: client.Transport}
+	return &recording
+}
+
+// statusTransport records the status code of the response into the pointer provided by the request context.
+type statusTransport struct {
+	base http.RoundTripper
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if resp != nil {
+		if statusCode, ok := req.Context().Value(statusKey{}).(*int); ok {
+			*statusCode = resp.StatusCode
+		}
+	}
+	return resp, err
+}
+
+type keySetErrKey struct{}
+
+// keySetErrRecorder wraps an [oidc.KeySet] to record the error of the signature verification into the pointer
+// provided by the context. The verification of the oidc package only keeps the message of the key set error,
+// but the type is needed to distinguish an unavailable JWKS endpoint from an invalid signature.
+type keySetErrRecorder struct {
+	oidc.KeySet
+}
+
+func (k *keySetErrRecorder) VerifySignature(ctx context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	payload, err := k.KeySet.VerifySignature(ctx, jws)
+	if err != nil {
+		if keySetErr, ok := ctx.Value(keySetErrKey{}).(*error); ok {
+			*keySetErr = err
+		}
+	}
+	return payload, err
+}
+
+// remoteKeySet wraps the [rp.NewRemoteKeySet], which only keeps the message of a failed request to the JWKS endpoint.
+// The result of the requests is recorded by the transport of the HTTP client, so that a failed signature verification
+// can be returned with an [authorization.HTTPStatusErr] or the network error, if the keys could not be fetched.
+type remoteKeySet struct {
+	oidc.KeySet
+	fetches  atomic.Uint64
+	fetchErr atomic.Pointer[error]
+}
+
+func newRemoteKeySet(httpClient *http.Client, jwksURI string) *remoteKeySet {
+	keySet := new(remoteKeySet)
+	recording := *httpClient
+	recording.Transport = &fetchTransport{base: httpClient.Transport, keySet: keySet}
+	keySet.KeySet = rp.NewRemoteKeySet(&recording, jwksURI)
+	return keySet
+}
+
+// VerifySignature implements the [oidc.KeySet] interface.
+// If the keys were fetched unsuccessfully during the verification, the error of the request is returned.
+func (k *remoteKeySet) VerifySignature(ctx context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	fetches := k.fetches.Load()
+	payload, err := k.KeySet.VerifySignature(ctx, jws)
+	if err == nil || k.fetches.Load() == fetches {
+		return payload, err
+	}
+	if fetchErr := k.fetchErr.Load(); fetchErr != nil {
+		return nil, fmt.Errorf("%w: %w", err, *fetchErr)
+	}
+	return nil, err
+}
+
+// fetchTransport records the result of the requests to the JWKS endpoint into the [remoteKeySet].
+type fetchTransport struct {
+	base   http.RoundTripper
+	keySet *remoteKeySet
+}
+
+func (t *fetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	var fetchErr error
+	switch {
+	case err != nil:
+		fetchErr = fmt.Errorf("unable to fetch jwks: %w", err)
+	case resp.StatusCode != http.StatusOK:
+		fetchErr = authorization.NewErrorHTTPStatus(resp.StatusCode, fmt.Errorf("unable to fetch jwks: %s", resp.Status))
+	}
+	if fetchErr != nil {
+		t.keySet.fetchErr.Store(&fetchErr)
+	} else {
+		t.keySet.fetchErr.Store(nil)
+	}
+	t.keySet.fetches.Add(1)
+	return resp, err
+}
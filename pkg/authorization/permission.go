@@ -0,0 +1,116 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrMissingPermission = errors.New("missing required permission")
+)
+
+// PermissionMapper maps the roles granted to a user to application permissions (e.g. `invoice.write`),
+// so that checks can be expressed on fine-grained permissions instead of roles.
+type PermissionMapper struct {
+	permissions map[string][]string
+}
+
+// NewPermissionMapper creates a [PermissionMapper] from a map of roles to their permissions.
+func NewPermissionMapper(rolePermissions map[string][]string) *PermissionMapper {
+	permissions := make(map[string][]string, len(rolePermissions))
+	for role, rolePermission := range rolePermissions {
+		permissions[role] = slices.Clone(rolePermission)
+	}
+	return &PermissionMapper{
+		permissions: permissions,
+	}
+}
+
+// ParsePermissionMapper creates a [PermissionMapper] from a YAML or JSON document
+// mapping roles to a list of permissions, e.g.:
+//
+//	admin: [invoice.read, invoice.write]
+//	accountant: [invoice.read]
+func ParsePermissionMapper(data []byte) (*PermissionMapper, error) {
+	var rolePermissions map[string][]string
+	if err := yaml.Unmarshal(data, &rolePermissions); err != nil {
+		return nil, fmt.Errorf("invalid permission mapping: %w", err)
+	}
+	return NewPermissionMapper(rolePermissions), nil
+}
+
+// LoadPermissionMapper creates a [PermissionMapper] from a YAML or JSON file (see [ParsePermissionMapper]).
+func LoadPermissionMapper(path string) (*PermissionMapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePermissionMapper(data)
+}
+
+// Permissions returns the (deduplicated and sorted) permissions of the provided roles.
+func (m *PermissionMapper) Permissions(roles ...string) []string {
+	var permissions []string
+	for _, role := range roles {
+		permissions = append(permissions, m.permissions[role]...)
+	}
+	slices.Sort(permissions)
+	return slices.Compact(permissions)
+}
+
+// HasPermission returns if any of the provided roles maps to the permission.
+func (m *PermissionMapper) HasPermission(permission string, roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(m.permissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPermission requires any of the roles granted to the user (see [RolesCtx.Roles]) to map to the permission.
+// If none does, an [ErrMissingPermission] is returned.
+func (m *PermissionMapper) WithPermission(permission string) CheckOption {
+	return m.withPermissionCheck(permission, func(rolesCtx RolesCtx) []string {
+		return rolesCtx.Roles()
+	})
+}
+
+// WithPermissionInOrganization requires any of the roles granted to the user in the organization
+// (see [RolesCtx.RolesInOrganization]) to map to the permission.
+// If none does, an [ErrMissingPermission] is returned.
+func (m *PermissionMapper) WithPermissionInOrganization(permission, organizationID string) CheckOption {
+	return m.withPermissionCheck(permission, func(rolesCtx RolesCtx) []string {
+		return rolesCtx.RolesInOrganization(organizationID)
+	})
+}
+
+// WithPermissionInProject requires any of the roles granted to the user in the project
+// (see [RolesCtx.RolesInProject]) to map to the permission.
+// If none does, an [ErrMissingPermission] is returned.
+func (m *PermissionMapper) WithPermissionInProject(permission, projectID string) CheckOption {
+	return m.withPermissionCheck(permission, func(rolesCtx RolesCtx) []string {
+		return rolesCtx.RolesInProject(projectID)
+	})
+}
+
+// withPermissionCheck adds a check requiring the authorization context to implement [RolesCtx].
+// If it does not, an [ErrUnsupportedContext] is returned.
+func (m *PermissionMapper) withPermissionCheck(permission string, roles func(rolesCtx RolesCtx) []string) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			rolesCtx, ok := authCtx.(RolesCtx)
+			if !ok {
+				return ErrUnsupportedContext
+			}
+			if !m.HasPermission(permission, roles(rolesCtx)...) {
+				return fmt.Errorf("%w: `%s`", ErrMissingPermission, permission)
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package authorization
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePermissionMapper(t *testing.T) {
+	want := NewPermissionMapper(map[string][]string{
+		"admin":      {"invoice.read", "invoice.write"},
+		"accountant": {"invoice.read"},
+	})
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			data: "admin: [invoice.read, invoice.write]\naccountant:\n  - invoice.read\n",
+		},
+		{
+			name: "json",
+			data: `{"admin": ["invoice.read", "invoice.write"], "accountant": ["invoice.read"]}`,
+		},
+		{
+			name:    "invalid",
+			data:    `{"admin": "invoice.read"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePermissionMapper([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestLoadPermissionMapper(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "permissions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("admin: [invoice.read, invoice.write]\n"), 0o600))
+
+	mapper, err := LoadPermissionMapper(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invoice.read", "invoice.write"}, mapper.Permissions("admin", "unknown"))
+
+	_, err = LoadPermissionMapper(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPermissionMapper_Permissions(t *testing.T) {
+	mapper := NewPermissionMapper(map[string][]string{
+		"admin":      {"invoice.write", "invoice.read"},
+		"accountant": {"invoice.read", "report.read"},
+	})
+	assert.Equal(t, []string{"invoice.read", "invoice.write", "report.read"}, mapper.Permissions("admin", "accountant"))
+	assert.Empty(t, mapper.Permissions("unknown"))
+	assert.True(t, mapper.HasPermission("report.read", "admin", "accountant"))
+	assert.False(t, mapper.HasPermission("report.read", "admin"))
+}
+
+func TestPermissionChecks(t *testing.T) {
+	mapper := NewPermissionMapper(map[string][]string{
+		"admin":      {"invoice.read", "invoice.write"},
+		"accountant": {"invoice.read"},
+	})
+	authCtx := &testCtx{
+		isAuthorized: true,
+		roles: map[string]map[string][]string{
+			"":        {"org1": {"accountant"}, "org2": {"admin"}},
+			"project": {"org1": {"admin"}},
+		},
+	}
+	tests := []struct {
+		name    string
+		options []CheckOption
+		wantErr error
+	}{
+		{
+			name:    "permission granted",
+			options: []CheckOption{mapper.WithPermission("invoice.write")},
+		},
+		{
+			name:    "permission missing",
+			options: []CheckOption{mapper.WithPermission("invoice.delete")},
+			wantErr: ErrMissingPermission,
+		},
+		{
+			name:    "permission granted in organization",
+			options: []CheckOption{mapper.WithPermissionInOrganization("invoice.read", "org1")},
+		},
+		{
+			name:    "permission missing in organization",
+			options: []CheckOption{mapper.WithPermissionInOrganization("invoice.write", "org1")},
+			wantErr: ErrMissingPermission,
+		},
+		{
+			name:    "permission granted in project",
+			options: []CheckOption{mapper.WithPermissionInProject("invoice.write", "project")},
+		},
+		{
+			name:    "permission missing in project",
+			options: []CheckOption{mapper.WithPermissionInProject("invoice.write", "other")},
+			wantErr: ErrMissingPermission,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestAuthorizer(authCtx).CheckAuthorization(context.Background(), "Bearer token", tt.options...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, NewErrorPermissionDenied(tt.wantErr))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPermissionChecks_UnsupportedContext(t *testing.T) {
+	authCtx, err := newTestAuthorizer(newTestBasicCtx()).CheckAuthorization(context.Background(), "Bearer token", NewPermissionMapper(nil).WithPermission("invoice.read"))
+	assert.ErrorIs(t, err, NewErrorPermissionDenied(ErrUnsupportedContext))
+	assert.Empty(t, Roles(WithAuthContext(context.Background(), authCtx)))
+}
+
+func TestRoles(t *testing.T) {
+	authCtx := &testCtx{
+		isAuthorized: true,
+		roles: map[string]map[string][]string{
+			"":        {"org1": {"accountant"}},
+			"project": {"org1": {"admin"}},
+		},
+	}
+	ctx := WithAuthContext(context.Background(), authCtx)
+	assert.Equal(t, []string{"accountant"}, Roles(ctx))
+	assert.Equal(t, []string{"accountant"}, RolesInOrganization(ctx, "org1"))
+	assert.Empty(t, RolesInOrganization(ctx, "org2"))
+	assert.Equal(t, []string{"admin"}, RolesInProject(ctx, "project"))
+	assert.Empty(t, Roles(context.Background()))
+}
@@ -0,0 +1,81 @@
+package authorization
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+type requestKey struct{}
+
+type requestInfoKey struct{}
+
+var (
+	ErrUnsupportedRequest = errors.New("request is not available for the check")
+)
+
+// WithRequest allows to set the request (e.g. the [net/http.Request] or the gRPC request message) of the authorization check,
+// which can later be retrieved by calling the [Request] function. It is used by the HTTP and gRPC interceptors
+// to provide the request to the checks of [WithRequestCheck].
+func WithRequest(ctx context.Context, req any) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// Request returns the request set by [WithRequest] or nil if none was set.
+func Request(ctx context.Context) any {
+	return ctx.Value(requestKey{})
+}
+
+// RequestInfo describes a request which is not an [*net/http.Request], e.g. a gRPC call, independent of its transport.
+// It is set by the gRPC and Connect interceptors (see [WithRequestInfo]) for the checks, which require information
+// about the request itself, e.g. the host to resolve the instance of a [MultiAuthorizer], the method and URI of DPoP proofs
+// or the client certificate of [WithCertificateBinding].
+type RequestInfo struct {
+	// Scheme is the URI scheme of the request, e.g. `https`.
+	Scheme string
+	// Host is the host (`:authority`) the request was sent to.
+	Host string
+	// Method is the HTTP method of the request, which is always `POST` for gRPC calls.
+	Method string
+	// Path is the path of the request, e.g. the full method name of a gRPC call (`/pkg.Service/Method`).
+	Path string
+	// DPoPProofs are the values of the DPoP header (or gRPC metadata), see [HeaderDPoP].
+	DPoPProofs []string
+	// ClientCertificate is the (leaf) client certificate of the mutual TLS connection or nil if none was presented.
+	ClientCertificate *x509.Certificate
+}
+
+// WithRequestInfo allows to set the [RequestInfo] of the authorization check,
+// which can later be retrieved by calling the [RequestInfoFromContext] function.
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the [RequestInfo] set by [WithRequestInfo] or nil if none was set.
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// WithRequestCheck allows a custom requirement comparing attributes of the request (e.g. the organization of a resource)
+// with the authorization context. The request is of type R, e.g. [*net/http.Request] in the HTTP middleware
+// or the request message (e.g. *pb.UpdateDocumentRequest) in the gRPC unary interceptor.
+// If no request of type R is available (e.g. in a gRPC stream), an [ErrUnsupportedRequest] is returned.
+// The name is used to identify the check in case of a failure, which returns a [CheckErr] wrapping an [ErrCheckFailed]
+// and the error of the check.
+func WithRequestCheck[R any](name string, check func(ctx context.Context, authCtx Ctx, req R) error) CheckOption {
+	return func(checks *Check[Ctx]) {
+		ctx := checks.Context()
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			req, ok := Request(ctx).(R)
+			if !ok {
+				return fmt.Errorf("%w: `%s`", ErrUnsupportedRequest, name)
+			}
+			if err := check(ctx, authCtx, req); err != nil {
+				return NewCheckErr(name, fmt.Errorf("%w: %w", ErrCheckFailed, err))
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	organizationID string
+}
+
+var errForeignOrganization = errors.New("resource belongs to another organization")
+
+func TestWithRequestCheck(t *testing.T) {
+	sameOrganization := WithRequestCheck("same organization", func(_ context.Context, authCtx Ctx, req *testRequest) error {
+		if req.organizationID != authCtx.OrganizationID() {
+			return errForeignOrganization
+		}
+		return nil
+	})
+	tests := []struct {
+		name    string
+		req     any
+		options []CheckOption
+		wantErr []error
+	}{
+		{
+			name:    "request matches",
+			req:     &testRequest{organizationID: "org"},
+			options: []CheckOption{sameOrganization},
+		},
+		{
+			name:    "request does not match",
+			req:     &testRequest{organizationID: "other"},
+			options: []CheckOption{sameOrganization},
+			wantErr: []error{ErrCheckFailed, errForeignOrganization},
+		},
+		{
+			name:    "no request",
+			options: []CheckOption{sameOrganization},
+			wantErr: []error{ErrUnsupportedRequest},
+		},
+		{
+			name:    "request of other type",
+			req:     testRequest{organizationID: "org"},
+			options: []CheckOption{sameOrganization},
+			wantErr: []error{ErrUnsupportedRequest},
+		},
+		{
+			name:    "anyOf, request check succeeds",
+			req:     &testRequest{organizationID: "org"},
+			options: []CheckOption{AnyOf(WithRole("admin"), sameOrganization)},
+		},
+		{
+			name:    "not, request check fails",
+			req:     &testRequest{organizationID: "other"},
+			options: []CheckOption{Not(sameOrganization)},
+		},
+		{
+			name:    "not, request check succeeds",
+			req:     &testRequest{organizationID: "org"},
+			options: []CheckOption{Not(sameOrganization)},
+			wantErr: []error{ErrNegatedCheck},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAuthorizer(&testCtx{isAuthorized: true, organizationID: "org"})
+			ctx := context.Background()
+			if tt.req != nil {
+				ctx = WithRequest(ctx, tt.req)
+			}
+			_, err := a.CheckAuthorization(ctx, "Bearer token", tt.options...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var permissionDenied *PermissionDeniedErr
+			assert.ErrorAs(t, err, &permissionDenied)
+			for _, wantErr := range tt.wantErr {
+				assert.ErrorIs(t, err, wantErr)
+			}
+		})
+	}
+}
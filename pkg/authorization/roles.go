@@ -0,0 +1,44 @@
+package authorization
+
+import "context"
+
+// RolesCtx is an optional extension of [Ctx] enumerating the roles granted to the authorized user.
+// It is required by [PermissionMapper.WithPermission].
+type RolesCtx interface {
+	// Roles returns all roles granted to the user (in any organization).
+	Roles() []string
+	// RolesInOrganization returns the roles granted to the user in the specified organization.
+	RolesInOrganization(organizationID string) []string
+	// RolesInProject returns the roles granted to the user in the specified project (in any organization).
+	RolesInProject(projectID string) []string
+}
+
+// Roles returns all roles granted to the authorized user (see [RolesCtx]).
+// In case of an unauthorized caller or a context not implementing [RolesCtx], it is empty.
+func Roles(ctx context.Context) []string {
+	rolesCtx, ok := Context[Ctx](ctx).(RolesCtx)
+	if !ok {
+		return nil
+	}
+	return rolesCtx.Roles()
+}
+
+// RolesInOrganization returns the roles granted to the authorized user in the specified organization (see [RolesCtx]).
+// In case of an unauthorized caller or a context not implementing [RolesCtx], it is empty.
+func RolesInOrganization(ctx context.Context, organizationID string) []string {
+	rolesCtx, ok := Context[Ctx](ctx).(RolesCtx)
+	if !ok {
+		return nil
+	}
+	return rolesCtx.RolesInOrganization(organizationID)
+}
+
+// RolesInProject returns the roles granted to the authorized user in the specified project (see [RolesCtx]).
+// In case of an unauthorized caller or a context not implementing [RolesCtx], it is empty.
+func RolesInProject(ctx context.Context, projectID string) []string {
+	rolesCtx, ok := Context[Ctx](ctx).(RolesCtx)
+	if !ok {
+		return nil
+	}
+	return rolesCtx.RolesInProject(projectID)
+}
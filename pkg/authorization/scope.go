@@ -0,0 +1,101 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMissingScope       = errors.New("missing required scope")
+	ErrMissingAudience    = errors.New("missing required audience")
+	ErrClientIDMismatch   = errors.New("client id does not match")
+	ErrUnsupportedContext = errors.New("authorization context does not provide the required information")
+)
+
+// TokenCtx is an optional extension of [Ctx] providing information about the scopes, audience and client of the access token.
+// It is required by [WithScope], [WithAnyScope], [WithAudience] and [WithClientID].
+type TokenCtx interface {
+	HasScope(scope string) bool
+	HasAudience(audience string) bool
+	GetClientID() string
+}
+
+// MissingScopeErr is returned by [WithScope] and [WithAnyScope] if the access token was not granted the required scopes.
+// It can be matched with [ErrMissingScope] and provides the required scopes, e.g. for an RFC 6750 `insufficient_scope` response.
+type MissingScopeErr struct {
+	Scopes []string
+}
+
+func (e *MissingScopeErr) Error() string {
+	return fmt.Sprintf("%s: `%s`", ErrMissingScope, strings.Join(e.Scopes, " "))
+}
+
+func (e *MissingScopeErr) Unwrap() error {
+	return ErrMissingScope
+}
+
+// WithScope requires the access token to be granted all the provided scopes.
+// If a scope is missing, a [MissingScopeErr] is returned.
+func WithScope(scopes ...string) CheckOption {
+	return withTokenCheck(func(tokenCtx TokenCtx) error {
+		for _, scope := range scopes {
+			if !tokenCtx.HasScope(scope) {
+				return &MissingScopeErr{Scopes: scopes}
+			}
+		}
+		return nil
+	})
+}
+
+// WithAnyScope requires the access token to be granted at least one of the provided scopes.
+// If none of the scopes is granted, a [MissingScopeErr] is returned.
+func WithAnyScope(scopes ...string) CheckOption {
+	return withTokenCheck(func(tokenCtx TokenCtx) error {
+		for _, scope := range scopes {
+			if tokenCtx.HasScope(scope) {
+				return nil
+			}
+		}
+		return &MissingScopeErr{Scopes: scopes}
+	})
+}
+
+// WithAudience requires the access token to be issued for the provided audience.
+// If the audience is missing, an [ErrMissingAudience] is returned.
+func WithAudience(audience string) CheckOption {
+	return withTokenCheck(func(tokenCtx TokenCtx) error {
+		if tokenCtx.HasAudience(audience) {
+			return nil
+		}
+		return fmt.Errorf("%w: `%s`", ErrMissingAudience, audience)
+	})
+}
+
+// WithClientID requires the access token to be issued to one of the provided clients.
+// If the client does not match, an [ErrClientIDMismatch] is returned.
+func WithClientID(clientIDs ...string) CheckOption {
+	return withTokenCheck(func(tokenCtx TokenCtx) error {
+		clientID := tokenCtx.GetClientID()
+		for _, id := range clientIDs {
+			if id == clientID {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: `%s`", ErrClientIDMismatch, clientID)
+	})
+}
+
+// withTokenCheck adds a check requiring the authorization context to implement [TokenCtx].
+// If it does not, an [ErrUnsupportedContext] is returned.
+func withTokenCheck(check func(tokenCtx TokenCtx) error) CheckOption {
+	return func(checks *Check[Ctx]) {
+		checks.Checks = append(checks.Checks, func(authCtx Ctx) error {
+			tokenCtx, ok := authCtx.(TokenCtx)
+			if !ok {
+				return ErrUnsupportedContext
+			}
+			return check(tokenCtx)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeChecks(t *testing.T) {
+	authCtx := &testCtx{
+		isAuthorized: true,
+		scopes:       []string{"openid", "invoices:read"},
+		audience:     []string{"project"},
+		clientID:     "client",
+	}
+	tests := []struct {
+		name       string
+		authCtx    *testCtx
+		options    []CheckOption
+		wantErr    error
+		wantScopes []string
+	}{
+		{
+			name:    "scope granted",
+			authCtx: authCtx,
+			options: []CheckOption{WithScope("openid", "invoices:read")},
+		},
+		{
+			name:       "scope missing",
+			authCtx:    authCtx,
+			options:    []CheckOption{WithScope("openid", "invoices:write")},
+			wantErr:    ErrMissingScope,
+			wantScopes: []string{"openid", "invoices:write"},
+		},
+		{
+			name:    "any scope granted",
+			authCtx: authCtx,
+			options: []CheckOption{WithAnyScope("invoices:write", "invoices:read")},
+		},
+		{
+			name:       "any scope missing",
+			authCtx:    authCtx,
+			options:    []CheckOption{WithAnyScope("invoices:write", "invoices:delete")},
+			wantErr:    ErrMissingScope,
+			wantScopes: []string{"invoices:write", "invoices:delete"},
+		},
+		{
+			name:    "audience matches",
+			authCtx: authCtx,
+			options: []CheckOption{WithAudience("project")},
+		},
+		{
+			name:    "audience missing",
+			authCtx: authCtx,
+			options: []CheckOption{WithAudience("other")},
+			wantErr: ErrMissingAudience,
+		},
+		{
+			name:    "client id matches",
+			authCtx: authCtx,
+			options: []CheckOption{WithClientID("other", "client")},
+		},
+		{
+			name:    "client id does not match",
+			authCtx: authCtx,
+			options: []CheckOption{WithClientID("other")},
+			wantErr: ErrClientIDMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestAuthorizer(tt.authCtx).CheckAuthorization(context.Background(), "Bearer token", tt.options...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, NewErrorPermissionDenied(tt.wantErr))
+			if tt.wantScopes != nil {
+				var scopeErr *MissingScopeErr
+				require.True(t, errors.As(err, &scopeErr))
+				assert.Equal(t, tt.wantScopes, scopeErr.Scopes)
+			}
+		})
+	}
+}
+
+func TestScopeChecks_UnsupportedContext(t *testing.T) {
+	_, err := newTestAuthorizer(newTestBasicCtx()).CheckAuthorization(context.Background(), "Bearer token", WithScope("openid"))
+	assert.ErrorIs(t, err, NewErrorPermissionDenied(ErrUnsupportedContext))
+}
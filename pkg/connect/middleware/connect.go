@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	grpcmw "github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+)
+
+// New creates a [connect.Interceptor] for handlers of Connect (https://connectrpc.com), enforcing the same checks
+// as [grpcmw.Interceptor.Unary] and [grpcmw.Interceptor.Stream] of the provided interceptor.
+// The checks are keyed by the procedure (e.g. `/pkg.Service/Method`), which equals the full method name of gRPC.
+// The request headers are provided as incoming metadata, so the configured [grpcmw.TokenExtractor] is used as well.
+// Errors are mapped by the [grpcmw.StatusMapper] and returned as [connect.Error] with the corresponding code and details.
+// The authorization context can be retrieved by [grpcmw.Interceptor.Context].
+//
+// Connect does not provide the host and the TLS connection of the call to interceptors. Wrap the Connect handler
+// with [Handler] to provide them as [authorization.RequestInfo], as they would be for a gRPC call.
+// Otherwise the instance of an [authorization.MultiAuthorizer] can only be resolved by the issuer of a JWT,
+// [authorization.WithCertificateBinding] rejects bound tokens and [authorization.WithDPoPOrigin] is required to verify DPoP proofs.
+func New[T authorization.Ctx](interceptor *grpcmw.Interceptor[T]) connect.Interceptor {
+	return &connectInterceptor[T]{interceptor: interceptor}
+}
+
+type requestKey struct{}
+
+// Handler wraps the [http.Handler] of Connect services (e.g. the one returned by a generated `New...Handler`)
+// to provide the host and the TLS connection of the [*http.Request] to the authorization checks of [New].
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), requestKey{}, req)))
+	})
+}
+
+type connectInterceptor[T authorization.Ctx] struct {
+	interceptor *grpcmw.Interceptor[T]
+}
+
+// WrapUnary implements [connect.Interceptor] by checking the authorization of unary handler calls.
+// The request message is provided to checks created by [authorization.WithRequestCheck].
+func (c *connectInterceptor[T]) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx, err := c.intercept(ctx, req.Spec().Procedure, req.Header(), req.Any())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements [connect.Interceptor] by not intercepting client calls.
+func (c *connectInterceptor[T]) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements [connect.Interceptor] by checking the authorization of streaming handler calls.
+// As for [grpcmw.Interceptor.Stream], checks created by [authorization.WithRequestCheck] will fail.
+func (c *connectInterceptor[T]) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := c.intercept(ctx, conn.Spec().Procedure, conn.RequestHeader(), nil)
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// intercept checks the authorization in a context providing the headers as incoming metadata
+// and the procedure as method of the [grpc.ServerTransportStream], as the checks would get it for a gRPC call.
+// The resulting authorization context is added to the original context.
+func (c *connectInterceptor[T]) intercept(ctx context.Context, procedure string, header http.Header, req any) (context.Context, error) {
+	md := make(metadata.MD, len(header))
+	for key, values := range header {
+		md.Append(strings.ToLower(key), values...)
+	}
+	info := &authorization.RequestInfo{
+		Scheme:     "https",
+		Method:     http.MethodPost,
+		Path:       procedure,
+		DPoPProofs: header.Values(authorization.HeaderDPoP),
+	}
+	if httpReq, ok := ctx.Value(requestKey{}).(*http.Request); ok {
+		info.Scheme = "http"
+		if httpReq.TLS != nil {
+			info.Scheme = "https"
+		}
+		info.Host = httpReq.Host
+		info.Method = httpReq.Method
+		info.Path = httpReq.URL.Path
+		if httpReq.TLS != nil && len(httpReq.TLS.PeerCertificates) > 0 {
+			info.ClientCertificate = httpReq.TLS.PeerCertificates[0]
+		}
+	}
+	checkCtx := metadata.NewIncomingContext(ctx, md)
+	checkCtx = grpc.NewContextWithServerTransportStream(checkCtx, &procedureStream{procedure: procedure})
+	authCtx, authorized, err := c.interceptor.Check(checkCtx, info, procedure, req)
+	if err != nil {
+		return nil, connectError(status.Convert(err))
+	}
+	if !authorized {
+		return ctx, nil
+	}
+	return authorization.WithAuthContext(ctx, authCtx), nil
+}
+
+// connectError converts the gRPC status into a [connect.Error] including its details.
+func connectError(st *status.Status) *connect.Error {
+	err := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, detail := range st.Proto().GetDetails() {
+		errorDetail, detailErr := connect.NewErrorDetail(detail)
+		if detailErr != nil {
+			continue
+		}
+		err.AddDetail(errorDetail)
+	}
+	return err
+}
+
+// procedureStream provides the procedure of a Connect call as method (see [grpc.Method]).
+// Headers and trailers cannot be set during the authorization check and are ignored.
+type procedureStream struct {
+	procedure string
+}
+
+var _ grpc.ServerTransportStream = (*procedureStream)(nil)
+
+func (s *procedureStream) Method() string {
+	return s.procedure
+}
+
+func (s *procedureStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *procedureStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *procedureStream) SetTrailer(metadata.MD) error {
+	return nil
+}
@@ -0,0 +1,185 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/connect/middleware"
+	grpcmw "github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+// TestNew verifies that the checks of the procedure are enforced on Connect handlers,
+// including request checks and the mapping of the error codes.
+func TestNew(t *testing.T) {
+	authorizer, err := authorization.New(context.Background(), zitadel.New("zitadel.invalid"),
+		func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*mockCtx], error) {
+			return &mockVerifier{ctx: &mockCtx{organizationID: "org"}}, nil
+		},
+	)
+	require.NoError(t, err)
+	interceptor := grpcmw.New[*mockCtx](authorizer, map[string][]authorization.CheckOption{
+		"/pkg.Service/Get": {authorization.WithRequestCheck("organization of request", func(_ context.Context, authCtx authorization.Ctx, req *wrapperspb.StringValue) error {
+			if req.GetValue() != authCtx.OrganizationID() {
+				return errors.New("foreign organization")
+			}
+			return nil
+		})},
+	}, grpcmw.WithPublicMethods[*mockCtx]("/pkg.Service/Public"))
+
+	mux := http.NewServeMux()
+	for _, procedure := range []string{"/pkg.Service/Get", "/pkg.Service/Public"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+			func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+				if authCtx := interceptor.Context(ctx); authCtx != nil {
+					return connect.NewResponse(wrapperspb.String(authCtx.OrganizationID())), nil
+				}
+				return connect.NewResponse(wrapperspb.String("")), nil
+			},
+			connect.WithInterceptors(middleware.New(interceptor)),
+		))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		procedure string
+		token     string
+		value     string
+		wantCode  connect.Code
+		wantOrgID string
+	}{
+		{
+			name:      "authorized",
+			procedure: "/pkg.Service/Get",
+			token:     "Bearer token",
+			value:     "org",
+			wantOrgID: "org",
+		},
+		{
+			name:      "missing token",
+			procedure: "/pkg.Service/Get",
+			value:     "org",
+			wantCode:  connect.CodeUnauthenticated,
+		},
+		{
+			name:      "request does not match",
+			procedure: "/pkg.Service/Get",
+			token:     "Bearer token",
+			value:     "other",
+			wantCode:  connect.CodePermissionDenied,
+		},
+		{
+			name:      "public",
+			procedure: "/pkg.Service/Public",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+tt.procedure)
+			req := connect.NewRequest(wrapperspb.String(tt.value))
+			if tt.token != "" {
+				req.Header().Set(authorization.HeaderName, tt.token)
+			}
+			resp, err := client.CallUnary(context.Background(), req)
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, connect.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOrgID, resp.Msg.GetValue())
+		})
+	}
+}
+
+// TestHandler verifies that the host and the TLS connection of the request are provided to the verifier.
+func TestHandler(t *testing.T) {
+	var (
+		info      *authorization.RequestInfo
+		headerErr error
+	)
+	authorizer, err := authorization.New(context.Background(), zitadel.New("zitadel.invalid"),
+		func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*mockCtx], error) {
+			return verifierFunc(func(ctx context.Context, _ string) (*mockCtx, error) {
+				info = authorization.RequestInfoFromContext(ctx)
+				headerErr = grpc.SetHeader(ctx, metadata.Pairs("key", "value"))
+				return &mockCtx{}, nil
+			}), nil
+		},
+	)
+	require.NoError(t, err)
+	interceptor := grpcmw.New[*mockCtx](authorizer, map[string][]authorization.CheckOption{"/pkg.Service/Get": nil})
+	handler := connect.NewUnaryHandler("/pkg.Service/Get",
+		func(context.Context, *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			return connect.NewResponse(wrapperspb.String("")), nil
+		},
+		connect.WithInterceptors(middleware.New(interceptor)),
+	)
+	server := httptest.NewUnstartedServer(middleware.Handler(handler))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	httpClient := server.Client()
+	// the certificate of the server is used as client certificate as well
+	httpClient.Transport.(*http.Transport).TLSClientConfig.Certificates = server.TLS.Certificates
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, server.URL+"/pkg.Service/Get")
+	req := connect.NewRequest(wrapperspb.String(""))
+	req.Header().Set(authorization.HeaderName, "Bearer token")
+	req.Header().Set(authorization.HeaderDPoP, "proof")
+	_, err = client.CallUnary(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	require.NotNil(t, info.ClientCertificate)
+	assert.Equal(t, server.Certificate().Raw, info.ClientCertificate.Raw)
+	info.ClientCertificate = nil
+	assert.Equal(t, &authorization.RequestInfo{
+		Scheme:     "https",
+		Host:       server.Listener.Addr().String(),
+		Method:     "POST",
+		Path:       "/pkg.Service/Get",
+		DPoPProofs: []string{"proof"},
+	}, info)
+	assert.NoError(t, headerErr)
+}
+
+type verifierFunc func(ctx context.Context, authorizationToken string) (*mockCtx, error)
+
+func (f verifierFunc) CheckAuthorization(ctx context.Context, authorizationToken string) (*mockCtx, error) {
+	return f(ctx, authorizationToken)
+}
+
+type mockVerifier struct {
+	ctx *mockCtx
+}
+
+func (m *mockVerifier) CheckAuthorization(_ context.Context, _ string) (*mockCtx, error) {
+	return m.ctx, nil
+}
+
+type mockCtx struct {
+	token          string
+	organizationID string
+}
+
+func (m *mockCtx) IsAuthorized() bool                           { return m != nil }
+func (m *mockCtx) OrganizationID() string                       { return m.organizationID }
+func (m *mockCtx) UserID() string                               { return "" }
+func (m *mockCtx) IsGrantedRole(_ string) bool                  { return false }
+func (m *mockCtx) IsGrantedRoleInProject(_, _, _ string) bool   { return false }
+func (m *mockCtx) IsGrantedRoleInOrganization(_, _ string) bool { return false }
+func (m *mockCtx) SetToken(token string)                        { m.token = token }
+func (m *mockCtx) GetToken() string                             { return m.token }
@@ -3,33 +3,116 @@ package middleware
 import (
 	"context"
 	"errors"
+	"net/http"
 
-	"github.com/grpc-ecosystem/go-grpc-middleware/v2/metadata"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
 )
 
 type Interceptor[T authorization.Ctx] struct {
-	authorizer authorization.AuthorizationChecker[T]
-	checks     map[string][]authorization.CheckOption
+	authorizer     authorization.AuthorizationChecker[T]
+	publicMethods  []string
+	methodOptions  []*methodOptions
+	statusMapper   StatusMapper
+	defaultDeny    bool
+	policies       *policies
+	tokenExtractor TokenExtractor
 }
 
-func New[T authorization.Ctx](authorizer authorization.AuthorizationChecker[T], checks map[string][]authorization.CheckOption) *Interceptor[T] {
-	return &Interceptor[T]{
-		authorizer: authorizer,
-		checks:     checks,
+// Option allows customization of the [Interceptor] such as default-deny, public methods or the [TokenExtractor].
+type Option[T authorization.Ctx] func(*Interceptor[T])
+
+// WithDefaultDeny denies access to all methods, which are neither configured with checks nor listed as public.
+// By default, the [Interceptor] allows public access to such methods.
+func WithDefaultDeny[T authorization.Ctx]() Option[T] {
+	return func(i *Interceptor[T]) {
+		i.defaultDeny = true
+	}
+}
+
+// WithPublicMethods explicitly allows public access to the provided methods.
+// Like the checks, methods can be provided as full method name or as wildcard for a whole service (e.g. `/pkg.Service/*`).
+// If checks are configured for the same method or wildcard, the checks take precedence.
+func WithPublicMethods[T authorization.Ctx](methods ...string) Option[T] {
+	return func(i *Interceptor[T]) {
+		i.publicMethods = append(i.publicMethods, methods...)
+	}
+}
+
+// New creates an [Interceptor] enforcing the provided checks per method.
+// The checks are keyed by the full method name (e.g. `/pkg.Service/Method`) or by a wildcard
+// for all methods of a service (e.g. `/pkg.Service/*`). Checks of an exact method take precedence over the wildcard.
+// Additional checks can be derived from protobuf method options using [WithMethodOptions].
+// Methods (of the checks or [WithPublicMethods]), which are neither a full method name nor a service wildcard
+// (e.g. `/pkg.Service/Get*`), never match and are reported as unknown by [Interceptor.Validate].
+// Use [NewStrict] to reject them instead.
+// New panics with an [ErrUnknownService] if a service of [WithMethodOptions] is not registered.
+func New[T authorization.Ctx](authorizer authorization.AuthorizationChecker[T], checks map[string][]authorization.CheckOption, options ...Option[T]) *Interceptor[T] {
+	interceptor, err := newInterceptor(authorizer, checks, false, options...)
+	if err != nil {
+		panic(err)
 	}
+	return interceptor
+}
+
+// NewStrict creates an [Interceptor] like [New], but returns an [ErrInvalidMethodPattern] if a method
+// (of the checks or [WithPublicMethods]) is neither a full method name nor a service wildcard (e.g. `/pkg.Service/Get*`)
+// and an [ErrUnknownService] if a service of [WithMethodOptions] is not registered.
+func NewStrict[T authorization.Ctx](authorizer authorization.AuthorizationChecker[T], checks map[string][]authorization.CheckOption, options ...Option[T]) (*Interceptor[T], error) {
+	return newInterceptor(authorizer, checks, true, options...)
+}
+
+// newInterceptor creates the [Interceptor]. It returns an error for unknown services of [WithMethodOptions]
+// and, if strict, for invalid method patterns.
+func newInterceptor[T authorization.Ctx](authorizer authorization.AuthorizationChecker[T], checks map[string][]authorization.CheckOption, strict bool, options ...Option[T]) (*Interceptor[T], error) {
+	interceptor := &Interceptor[T]{
+		authorizer:     authorizer,
+		statusMapper:   DefaultStatusMapper,
+		tokenExtractor: TokenFromAuthorizationMetadata(),
+	}
+	for _, option := range options {
+		option(interceptor)
+	}
+	interceptor.policies = newPolicies()
+	var errs []error
+	for _, options := range interceptor.methodOptions {
+		resolved, err := options.resolve()
+		if err != nil {
+			return nil, err
+		}
+		for method, methodChecks := range resolved {
+			if isOverridden(method, checks) {
+				continue
+			}
+			errs = append(errs, interceptor.policies.add(method, &policy{checks: methodChecks}))
+		}
+	}
+	for method, checks := range checks {
+		errs = append(errs, interceptor.policies.add(method, &policy{checks: checks}))
+	}
+	for _, method := range interceptor.publicMethods {
+		errs = append(errs, interceptor.policies.add(method, &policy{public: true}))
+	}
+	if !strict {
+		return interceptor, nil
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return interceptor, nil
 }
 
 // Unary creates a [grpc.UnaryServerInterceptor].
 // Ensure to configure the [Interceptor] with the required checks.
-// If no checks are provided the interceptor will allow public access to the API.
+// The request message is provided to checks created by [authorization.WithRequestCheck].
+// If no checks are provided the interceptor will allow public access to the API, unless [WithDefaultDeny] is set.
 func (i *Interceptor[T]) Unary() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		ctx, err = i.intercept(ctx, info.FullMethod)
+		ctx, err = i.intercept(ctx, info.FullMethod, req)
 		if err != nil {
 			return nil, err
 		}
@@ -39,10 +122,11 @@ func (i *Interceptor[T]) Unary() grpc.UnaryServerInterceptor {
 
 // Stream creates a [grpc.StreamServerInterceptor].
 // Ensure to configure the [Interceptor] with the required checks.
-// If no checks are provided the interceptor will allow public access to the API.
+// Since the messages are received after the authorization check, checks created by [authorization.WithRequestCheck] will fail.
+// If no checks are provided the interceptor will allow public access to the API, unless [WithDefaultDeny] is set.
 func (i *Interceptor[T]) Stream() grpc.StreamServerInterceptor {
 	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		ctx, err := i.intercept(stream.Context(), info.FullMethod)
+		ctx, err := i.intercept(stream.Context(), info.FullMethod, nil)
 		if err != nil {
 			return err
 		}
@@ -55,21 +139,63 @@ func (i *Interceptor[T]) Context(ctx context.Context) T {
 	return authorization.Context[T](ctx)
 }
 
-func (i *Interceptor[T]) intercept(ctx context.Context, method string) (context.Context, error) {
-	for endpoint, checks := range i.checks {
-		if endpoint != method {
-			continue
+func (i *Interceptor[T]) intercept(ctx context.Context, method string, req any) (context.Context, error) {
+	authCtx, authorized, err := i.Check(ctx, requestInfo(ctx, method), method, req)
+	if err != nil {
+		return nil, err
+	}
+	if !authorized {
+		return ctx, nil
+	}
+	return authorization.WithAuthContext(ctx, authCtx), nil
+}
+
+// requestInfo returns the [authorization.RequestInfo] of an incoming gRPC call,
+// which is always a `POST` request to `https://{authority}/{full method name}`.
+func requestInfo(ctx context.Context, method string) *authorization.RequestInfo {
+	info := &authorization.RequestInfo{
+		Scheme:     "https",
+		Method:     http.MethodPost,
+		Path:       method,
+		DPoPProofs: metadata.ValueFromIncomingContext(ctx, authorization.HeaderDPoP),
+	}
+	if authority := metadata.ValueFromIncomingContext(ctx, ":authority"); len(authority) > 0 {
+		info.Host = authority[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+			info.ClientCertificate = tlsInfo.State.PeerCertificates[0]
 		}
-		authCtx, err := i.authorizer.CheckAuthorization(ctx, metadata.ExtractIncoming(ctx).Get(authorization.HeaderName), checks...)
-		if err != nil {
-			if errors.Is(err, &authorization.UnauthorizedErr{}) {
-				return nil, status.Error(codes.Unauthenticated, err.Error())
-			}
-			return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return info
+}
+
+// Check enforces the policy of the method and returns the authorization context of the caller.
+// The request and its info are provided to the checks. The token is taken from the incoming metadata of the context
+// by the [TokenExtractor]. It allows other transports (e.g. the Connect interceptor of the `pkg/connect/middleware` package)
+// to enforce the same checks as [Interceptor.Unary] and [Interceptor.Stream].
+// If the method is public, no context is returned and authorized is false.
+// Errors are already mapped to a gRPC status error by the [StatusMapper].
+func (i *Interceptor[T]) Check(ctx context.Context, info *authorization.RequestInfo, method string, req any) (authCtx T, authorized bool, err error) {
+	pol, ok := i.policies.lookup(method)
+	if !ok {
+		if i.defaultDeny {
+			return authCtx, false, i.statusMapper(ctx, method, authorization.NewErrorPermissionDenied(ErrNoPolicy)).Err()
 		}
-		return authorization.WithAuthContext(ctx, authCtx), nil
+		return authCtx, false, nil
+	}
+	if pol.public {
+		return authCtx, false, nil
+	}
+	checkCtx := authorization.WithRequestInfo(ctx, info)
+	if req != nil {
+		checkCtx = authorization.WithRequest(checkCtx, req)
+	}
+	authCtx, err = i.authorizer.CheckAuthorization(checkCtx, i.tokenExtractor(ctx), pol.checks...)
+	if err != nil {
+		return authCtx, false, i.statusMapper(ctx, method, err).Err()
 	}
-	return ctx, nil
+	return authCtx, true, nil
 }
 
 // serverStream is required to be able to intercept and annotate the [context.Context]
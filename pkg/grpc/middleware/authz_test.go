@@ -0,0 +1,316 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
+)
+
+// TestInterceptor_Unary verifies the method lookup with exact names, service wildcards,
+// public methods and default-deny.
+func TestInterceptor_Unary(t *testing.T) {
+	checks := map[string][]authorization.CheckOption{
+		"/pkg.Service/*":         {authorization.WithRole("reader")},
+		"/pkg.Service/Delete":    {authorization.WithRole("admin")},
+		"/pkg.Protected/Get":     nil,
+		"/pkg.Public/Restricted": nil,
+	}
+	tests := []struct {
+		name        string
+		options     []middleware.Option[*mockCtx]
+		checker     *mockChecker
+		method      string
+		wantCode    codes.Code
+		wantChecked bool
+	}{
+		{
+			name:        "exact method",
+			checker:     &mockChecker{ctx: &mockCtx{}},
+			method:      "/pkg.Protected/Get",
+			wantCode:    codes.OK,
+			wantChecked: true,
+		},
+		{
+			name:        "service wildcard",
+			checker:     &mockChecker{err: authorization.NewErrorUnauthorized(errors.New("invalid token"))},
+			method:      "/pkg.Service/List",
+			wantCode:    codes.Unauthenticated,
+			wantChecked: true,
+		},
+		{
+			name:        "exact method takes precedence over wildcard",
+			checker:     &mockChecker{err: authorization.NewErrorPermissionDenied(authorization.ErrMissingRole)},
+			method:      "/pkg.Service/Delete",
+			wantCode:    codes.PermissionDenied,
+			wantChecked: true,
+		},
+		{
+			name:     "unknown method, default allow",
+			checker:  &mockChecker{},
+			method:   "/pkg.Other/Get",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "unknown method, default deny",
+			options:  []middleware.Option[*mockCtx]{middleware.WithDefaultDeny[*mockCtx]()},
+			checker:  &mockChecker{},
+			method:   "/pkg.Other/Get",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "public method, default deny",
+			options: []middleware.Option[*mockCtx]{
+				middleware.WithDefaultDeny[*mockCtx](),
+				middleware.WithPublicMethods[*mockCtx]("/pkg.Public/*"),
+			},
+			checker:  &mockChecker{},
+			method:   "/pkg.Public/Get",
+			wantCode: codes.OK,
+		},
+		{
+			name: "checks take precedence over public method",
+			options: []middleware.Option[*mockCtx]{
+				middleware.WithPublicMethods[*mockCtx]("/pkg.Public/*", "/pkg.Public/Restricted"),
+			},
+			checker:     &mockChecker{err: authorization.NewErrorUnauthorized(authorization.ErrMissingToken)},
+			method:      "/pkg.Public/Restricted",
+			wantCode:    codes.Unauthenticated,
+			wantChecked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := middleware.New[*mockCtx](tt.checker, checks, tt.options...)
+			_, err := interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				},
+			)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantChecked, tt.checker.called)
+		})
+	}
+}
+
+// TestInterceptor_Validate verifies that unprotected methods and unknown policies are reported.
+func TestInterceptor_Validate(t *testing.T) {
+	services := map[string]grpc.ServiceInfo{
+		"pkg.Service": {Methods: []grpc.MethodInfo{{Name: "Get"}, {Name: "List"}}},
+		"pkg.Other":   {Methods: []grpc.MethodInfo{{Name: "Get"}, {Name: "Health"}}},
+	}
+	interceptor := middleware.New[*mockCtx](&mockChecker{}, map[string][]authorization.CheckOption{
+		"/pkg.Service/*":    nil,
+		"/pkg.Other/Get":    nil,
+		"/pkg.Other/Delete": nil,
+		"/pkg.Unknown/*":    nil,
+	})
+	err := interceptor.Validate(services)
+	var validationErr *middleware.MethodValidationErr
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{"/pkg.Other/Health"}, validationErr.Unprotected)
+	assert.Equal(t, []string{"/pkg.Other/Delete", "/pkg.Unknown/*"}, validationErr.Unknown)
+
+	interceptor = middleware.New[*mockCtx](&mockChecker{}, map[string][]authorization.CheckOption{
+		"/pkg.Service/*": nil,
+		"/pkg.Other/Get": nil,
+	}, middleware.WithPublicMethods[*mockCtx]("/pkg.Other/Health"))
+	assert.NoError(t, interceptor.Validate(services))
+}
+
+// TestNew_InvalidMethodPattern verifies that only full methods and service wildcards are matched
+// and that other patterns are rejected by NewStrict and reported by Validate.
+func TestNew_InvalidMethodPattern(t *testing.T) {
+	for _, method := range []string{"/pkg.Service/Get*", "/pkg.*/Get", "/*", "pkg.Service/Get", "/pkg.Service/", "/pkg.Service/Get/Other"} {
+		t.Run(method, func(t *testing.T) {
+			_, err := middleware.NewStrict[*mockCtx](&mockChecker{}, map[string][]authorization.CheckOption{method: nil})
+			assert.EqualError(t, err, "invalid method pattern: `"+method+"`, expected `/pkg.Service/Method` or `/pkg.Service/*`")
+			_, err = middleware.NewStrict[*mockCtx](&mockChecker{}, nil, middleware.WithPublicMethods[*mockCtx](method))
+			assert.ErrorIs(t, err, middleware.ErrInvalidMethodPattern)
+
+			checker := &mockChecker{}
+			interceptor := middleware.New[*mockCtx](checker, map[string][]authorization.CheckOption{method: nil})
+			_, err = interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				},
+			)
+			assert.NoError(t, err)
+			assert.False(t, checker.called)
+			var validationErr *middleware.MethodValidationErr
+			require.ErrorAs(t, interceptor.Validate(nil), &validationErr)
+			assert.Equal(t, []string{method}, validationErr.Unknown)
+		})
+	}
+}
+
+// TestInterceptor_RequestCheck verifies that the request message of unary calls is provided
+// to checks created by authorization.WithRequestCheck and that stream calls are denied.
+func TestInterceptor_RequestCheck(t *testing.T) {
+	authorizer, err := authorization.New(context.Background(), zitadel.New("zitadel.invalid"),
+		func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*mockCtx], error) {
+			return &mockVerifier{ctx: &mockCtx{organizationID: "org"}}, nil
+		},
+	)
+	require.NoError(t, err)
+	interceptor := middleware.New[*mockCtx](authorizer, map[string][]authorization.CheckOption{
+		"/pkg.Service/*": {authorization.WithRequestCheck("organization of request", func(_ context.Context, authCtx authorization.Ctx, req *wrapperspb.StringValue) error {
+			if req.GetValue() != authCtx.OrganizationID() {
+				return errors.New("foreign organization")
+			}
+			return nil
+		})},
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorization.HeaderName, "Bearer token"))
+
+	tests := []struct {
+		name     string
+		req      any
+		wantCode codes.Code
+	}{
+		{
+			name:     "request matches",
+			req:      wrapperspb.String("org"),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "request does not match",
+			req:      wrapperspb.String("other"),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "request of other type",
+			req:      wrapperspb.Int64(1),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor.Unary()(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				},
+			)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		err := interceptor.Stream()(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/pkg.Service/Watch"},
+			func(srv any, stream grpc.ServerStream) error {
+				return nil
+			},
+		)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+// TestInterceptor_RequestInfo verifies that the information of the gRPC call is provided to the verifier.
+func TestInterceptor_RequestInfo(t *testing.T) {
+	var info *authorization.RequestInfo
+	authorizer, err := authorization.New(context.Background(), zitadel.New("zitadel.invalid"),
+		func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*mockCtx], error) {
+			return verifierFunc(func(ctx context.Context, _ string) (*mockCtx, error) {
+				info = authorization.RequestInfoFromContext(ctx)
+				return &mockCtx{}, nil
+			}), nil
+		},
+	)
+	require.NoError(t, err)
+	interceptor := middleware.New[*mockCtx](authorizer, map[string][]authorization.CheckOption{"/pkg.Service/Get": nil})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		":authority", "api.example.com",
+		authorization.HeaderName, "Bearer token",
+		authorization.HeaderDPoP, "proof",
+	))
+	certificate := &x509.Certificate{Raw: []byte("certificate")}
+	ctx = peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}}},
+	})
+
+	_, err = interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, &authorization.RequestInfo{
+		Scheme:            "https",
+		Host:              "api.example.com",
+		Method:            "POST",
+		Path:              "/pkg.Service/Get",
+		DPoPProofs:        []string{"proof"},
+		ClientCertificate: certificate,
+	}, info)
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (m *mockServerStream) Context() context.Context {
+	return m.ctx
+}
+
+type mockChecker struct {
+	ctx     *mockCtx
+	err     error
+	called  bool
+	token   string
+	options []authorization.CheckOption
+}
+
+func (m *mockChecker) CheckAuthorization(_ context.Context, token string, options ...authorization.CheckOption) (*mockCtx, error) {
+	m.called = true
+	m.token = token
+	m.options = options
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.ctx, nil
+}
+
+type mockVerifier struct {
+	ctx *mockCtx
+}
+
+func (m *mockVerifier) CheckAuthorization(_ context.Context, _ string) (*mockCtx, error) {
+	return m.ctx, nil
+}
+
+type mockCtx struct {
+	token          string
+	organizationID string
+}
+
+func (m *mockCtx) IsAuthorized() bool                           { return m != nil }
+func (m *mockCtx) OrganizationID() string                       { return m.organizationID }
+func (m *mockCtx) UserID() string                               { return "" }
+func (m *mockCtx) IsGrantedRole(_ string) bool                  { return false }
+func (m *mockCtx) IsGrantedRoleInProject(_, _, _ string) bool   { return false }
+func (m *mockCtx) IsGrantedRoleInOrganization(_, _ string) bool { return false }
+func (m *mockCtx) SetToken(token string)                        { m.token = token }
+func (m *mockCtx) GetToken() string                             { return m.token }
+
+type verifierFunc func(ctx context.Context, authorizationToken string) (*mockCtx, error)
+
+func (f verifierFunc) CheckAuthorization(ctx context.Context, authorizationToken string) (*mockCtx, error) {
+	return f(ctx, authorizationToken)
+}
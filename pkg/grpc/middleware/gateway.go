@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+// GatewayAnnotator creates a metadata annotator for the grpc-gateway (see `runtime.WithMetadata`), which forwards the token
+// of the HTTP request as `authorization` metadata, so that the [Interceptor] of the gRPC server enforces its checks
+// on calls proxied by the gateway as well. The token is taken by the provided extractor, e.g. a
+// [github.com/zitadel/zitadel-go/v3/pkg/http/middleware.TokenExtractor] like TokenFromCookie for browsers
+// or multiple ones combined by ChainTokenExtractors. The Authorization header is always forwarded by the gateway itself.
+//
+// The gateway needs to call the server through a client connection (e.g. `RegisterServiceHandlerFromEndpoint`),
+// since handlers registered by `RegisterServiceHandlerServer` are called directly, without any interceptor.
+// DPoP proofs cannot be forwarded, since they are bound to the HTTP method and URL of the gateway.
+func GatewayAnnotator(extractor func(req *http.Request) string) func(ctx context.Context, req *http.Request) metadata.MD {
+	return func(_ context.Context, req *http.Request) metadata.MD {
+		token := extractor(req)
+		if token == "" || token == req.Header.Get(authorization.HeaderName) {
+			return nil
+		}
+		return metadata.Pairs(authorization.HeaderName, token)
+	}
+}
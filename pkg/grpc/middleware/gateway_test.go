@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+	httpmw "github.com/zitadel/zitadel-go/v3/pkg/http/middleware"
+)
+
+// TestGatewayAnnotator verifies that tokens of the HTTP request are forwarded as authorization metadata,
+// unless the gateway already forwards them as Authorization header.
+func TestGatewayAnnotator(t *testing.T) {
+	annotator := middleware.GatewayAnnotator(httpmw.ChainTokenExtractors(
+		httpmw.TokenFromAuthorizationHeader(),
+		httpmw.TokenFromCookie("access_token"),
+	))
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   metadata.MD
+	}{
+		{
+			name:   "authorization header",
+			header: map[string]string{"Authorization": "Bearer header"},
+		},
+		{
+			name:   "cookie",
+			header: map[string]string{"Cookie": "theme=dark; access_token=cookie"},
+			want:   metadata.Pairs("authorization", "Bearer cookie"),
+		},
+		{
+			name: "no token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://api.example.com/v1/resource", nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.want, annotator(context.Background(), req))
+		})
+	}
+}
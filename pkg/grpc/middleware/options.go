@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/client/zitadel/authoption"
+)
+
+const (
+	// PermissionAuthenticated is the ZITADEL [authoption.AuthOption] permission requiring only a valid authorization.
+	PermissionAuthenticated = "authenticated"
+)
+
+var (
+	ErrUnknownService = errors.New("unknown service")
+)
+
+// MethodOptionsResolver returns the checks required to call the provided method, e.g. by reading its custom method options.
+// If the method does not define any requirements, ok must be false.
+type MethodOptionsResolver func(method protoreflect.MethodDescriptor) (checks []authorization.CheckOption, ok bool)
+
+// methodOptions is the configuration of [WithMethodOptions].
+type methodOptions struct {
+	resolver MethodOptionsResolver
+	services []string
+}
+
+// WithMethodOptions builds the checks of the [Interceptor] from the method options of the registered services
+// using the provided [MethodOptionsResolver] (e.g. [AuthOptionResolver]).
+// The services are looked up by their full name (e.g. `pkg.Service`) in the [protoregistry.GlobalFiles].
+// They must be listed explicitly, since the registry contains every linked service, including ones of clients and dependencies.
+// If a service is not registered (e.g. because of a typo or a missing import of the generated code), its methods would
+// be left without checks. Therefore, [New] panics and [NewStrict] returns an [ErrUnknownService].
+// Checks explicitly passed to [New] for a method or for the wildcard of its service take precedence.
+func WithMethodOptions[T authorization.Ctx](resolver MethodOptionsResolver, service string, services ...string) Option[T] {
+	return func(i *Interceptor[T]) {
+		i.methodOptions = append(i.methodOptions, &methodOptions{
+			resolver: resolver,
+			services: append([]string{service}, services...),
+		})
+	}
+}
+
+// AuthOptionResolver creates a [MethodOptionsResolver] reading the ZITADEL `zitadel.v1.auth_option` method option ([authoption.AuthOption]).
+// The permission of the option is mapped to checks by the provided function, e.g. [RolePermission].
+func AuthOptionResolver(permissionChecks func(permission string) []authorization.CheckOption) MethodOptionsResolver {
+	return func(method protoreflect.MethodDescriptor) ([]authorization.CheckOption, bool) {
+		options := method.Options()
+		if options == nil || !proto.HasExtension(options, authoption.E_AuthOption) {
+			return nil, false
+		}
+		option, ok := proto.GetExtension(options, authoption.E_AuthOption).(*authoption.AuthOption)
+		if !ok || option.GetPermission() == "" {
+			return nil, false
+		}
+		return permissionChecks(option.GetPermission()), true
+	}
+}
+
+// RolePermission maps the [PermissionAuthenticated] permission to a plain authorization check
+// and any other permission to an [authorization.WithRole] check requiring a role with the same name.
+func RolePermission(permission string) []authorization.CheckOption {
+	if permission == PermissionAuthenticated {
+		return nil
+	}
+	return []authorization.CheckOption{authorization.WithRole(permission)}
+}
+
+// resolve calls the resolver for every method of the configured services
+// and returns the checks keyed by the full method name (`/pkg.Service/Method`).
+func (o *methodOptions) resolve() (map[string][]authorization.CheckOption, error) {
+	services, err := o.serviceDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	checks := make(map[string][]authorization.CheckOption)
+	for _, service := range services {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			methodChecks, ok := o.resolver(method)
+			if !ok {
+				continue
+			}
+			checks["/"+string(service.FullName())+"/"+string(method.Name())] = methodChecks
+		}
+	}
+	return checks, nil
+}
+
+// serviceDescriptors returns the descriptors of the configured services.
+// If a service is not registered, an [ErrUnknownService] is returned.
+func (o *methodOptions) serviceDescriptors() ([]protoreflect.ServiceDescriptor, error) {
+	descriptors := make([]protoreflect.ServiceDescriptor, 0, len(o.services))
+	for _, name := range o.services {
+		descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("%w: `%s`: %w", ErrUnknownService, name, err)
+		}
+		service, ok := descriptor.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%w: `%s` is not a service", ErrUnknownService, name)
+		}
+		descriptors = append(descriptors, service)
+	}
+	return descriptors, nil
+}
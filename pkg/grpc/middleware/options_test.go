@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/client/zitadel/auth"
+	"github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+)
+
+// TestWithMethodOptions verifies that the checks are built from the `zitadel.v1.auth_option`
+// method options of the ZITADEL AuthService and that explicit checks take precedence.
+func TestWithMethodOptions(t *testing.T) {
+	service := auth.AuthService_ServiceDesc.ServiceName
+	tests := []struct {
+		name        string
+		checks      map[string][]authorization.CheckOption
+		method      string
+		wantChecked bool
+		wantOptions int
+	}{
+		{
+			name:        "authenticated permission",
+			method:      auth.AuthService_GetMyUser_FullMethodName,
+			wantChecked: true,
+			wantOptions: 0,
+		},
+		{
+			name:        "role permission",
+			method:      auth.AuthService_RemoveMyUser_FullMethodName,
+			wantChecked: true,
+			wantOptions: 1,
+		},
+		{
+			name:        "no auth option",
+			method:      auth.AuthService_Healthz_FullMethodName,
+			wantChecked: false,
+		},
+		{
+			name: "explicit method check overrides auth option",
+			checks: map[string][]authorization.CheckOption{
+				auth.AuthService_RemoveMyUser_FullMethodName: {authorization.WithRole("a"), authorization.WithRole("b")},
+			},
+			method:      auth.AuthService_RemoveMyUser_FullMethodName,
+			wantChecked: true,
+			wantOptions: 2,
+		},
+		{
+			name: "explicit service wildcard overrides auth option",
+			checks: map[string][]authorization.CheckOption{
+				"/" + service + "/*": nil,
+			},
+			method:      auth.AuthService_RemoveMyUser_FullMethodName,
+			wantChecked: true,
+			wantOptions: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &mockChecker{ctx: &mockCtx{}}
+			interceptor := middleware.New[*mockCtx](checker, tt.checks,
+				middleware.WithMethodOptions[*mockCtx](middleware.AuthOptionResolver(middleware.RolePermission), service),
+			)
+			_, err := interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				},
+			)
+			assert.Equal(t, codes.OK, status.Code(err))
+			assert.Equal(t, tt.wantChecked, checker.called)
+			assert.Len(t, checker.options, tt.wantOptions)
+		})
+	}
+}
+
+// TestWithMethodOptions_UnknownService verifies that services, which are not registered, are not silently ignored.
+func TestWithMethodOptions_UnknownService(t *testing.T) {
+	option := middleware.WithMethodOptions[*mockCtx](middleware.AuthOptionResolver(middleware.RolePermission), "zitadel.auth.v1.Unknown")
+
+	_, err := middleware.NewStrict[*mockCtx](&mockChecker{}, nil, option)
+	assert.ErrorIs(t, err, middleware.ErrUnknownService)
+	assert.Panics(t, func() {
+		middleware.New[*mockCtx](&mockChecker{}, nil, option)
+	})
+}
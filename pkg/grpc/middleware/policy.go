@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+const (
+	// wildcardSuffix allows to define checks for all methods of a service, e.g. `/pkg.Service/*`.
+	wildcardSuffix = "*"
+)
+
+var (
+	ErrNoPolicy             = errors.New("no authorization policy for method")
+	ErrInvalidMethodPattern = errors.New("invalid method pattern")
+)
+
+// policy describes the authorization requirements of a method or service.
+type policy struct {
+	checks []authorization.CheckOption
+	public bool
+}
+
+// policies provides an O(1) lookup of the [policy] of a full method,
+// either by its exact name or by the wildcard of its service.
+type policies struct {
+	methods  map[string]*policy
+	services map[string]*policy
+}
+
+func newPolicies() *policies {
+	return &policies{
+		methods:  make(map[string]*policy),
+		services: make(map[string]*policy),
+	}
+}
+
+// add registers the policy for the provided method or service wildcard (e.g. `/pkg.Service/*`).
+// Existing policies with checks are not overwritten by public ones.
+// Any other pattern is registered as well, so it is reported by [Interceptor.Validate], but since it never matches,
+// an [ErrInvalidMethodPattern] is returned.
+func (p *policies) add(method string, pol *policy) error {
+	if !isMethodPattern(method) {
+		p.methods[method] = pol
+		return fmt.Errorf("%w: `%s`, expected `/pkg.Service/Method` or `/pkg.Service/*`", ErrInvalidMethodPattern, method)
+	}
+	target := p.methods
+	if service, ok := strings.CutSuffix(method, wildcardSuffix); ok {
+		target = p.services
+		method = service
+	}
+	if existing, ok := target[method]; ok && pol.public && !existing.public {
+		return nil
+	}
+	target[method] = pol
+	return nil
+}
+
+// isMethodPattern returns if the pattern is a full method (`/pkg.Service/Method`) or a service wildcard (`/pkg.Service/*`).
+func isMethodPattern(pattern string) bool {
+	fullMethod, ok := strings.CutPrefix(pattern, "/")
+	if !ok {
+		return false
+	}
+	service, method, ok := strings.Cut(fullMethod, "/")
+	if !ok || service == "" || method == "" || strings.Contains(service, wildcardSuffix) || strings.Contains(method, "/") {
+		return false
+	}
+	return method == wildcardSuffix || !strings.Contains(method, wildcardSuffix)
+}
+
+// lookup returns the policy of the full method (`/pkg.Service/Method`).
+// An exact match takes precedence over the service wildcard.
+func (p *policies) lookup(fullMethod string) (*policy, bool) {
+	if pol, ok := p.methods[fullMethod]; ok {
+		return pol, true
+	}
+	service, ok := serviceOf(fullMethod)
+	if !ok {
+		return nil, false
+	}
+	pol, ok := p.services[service]
+	return pol, ok
+}
+
+// serviceOf returns the service part of the full method including the trailing slash (`/pkg.Service/`).
+func serviceOf(fullMethod string) (string, bool) {
+	idx := strings.LastIndex(fullMethod, "/")
+	if idx < 0 {
+		return "", false
+	}
+	return fullMethod[:idx+1], true
+}
+
+// isOverridden returns if the checks contain an entry for the full method itself or the wildcard of its service.
+func isOverridden(fullMethod string, checks map[string][]authorization.CheckOption) bool {
+	if _, ok := checks[fullMethod]; ok {
+		return true
+	}
+	service, ok := serviceOf(fullMethod)
+	if !ok {
+		return false
+	}
+	_, ok = checks[service+wildcardSuffix]
+	return ok
+}
+
+// Validate cross-checks the configured checks and public methods against the registered services,
+// e.g. by passing [grpc.Server.GetServiceInfo] at startup.
+// It returns a [MethodValidationErr] listing the methods without a policy (unprotected)
+// and the policies, which do not match any registered method or service (unknown).
+func (i *Interceptor[T]) Validate(services map[string]grpc.ServiceInfo) error {
+	err := new(MethodValidationErr)
+	knownMethods := make(map[string]struct{})
+	knownServices := make(map[string]struct{}, len(services))
+	for name, info := range services {
+		prefix := "/" + name + "/"
+		knownServices[prefix] = struct{}{}
+		for _, method := range info.Methods {
+			fullMethod := prefix + method.Name
+			knownMethods[fullMethod] = struct{}{}
+			if _, ok := i.policies.lookup(fullMethod); !ok {
+				err.Unprotected = append(err.Unprotected, fullMethod)
+			}
+		}
+	}
+	for method := range i.policies.methods {
+		if _, ok := knownMethods[method]; !ok {
+			err.Unknown = append(err.Unknown, method)
+		}
+	}
+	for service := range i.policies.services {
+		if _, ok := knownServices[service]; !ok {
+			err.Unknown = append(err.Unknown, service+wildcardSuffix)
+		}
+	}
+	if len(err.Unprotected) == 0 && len(err.Unknown) == 0 {
+		return nil
+	}
+	slices.Sort(err.Unprotected)
+	slices.Sort(err.Unknown)
+	return err
+}
+
+// MethodValidationErr is returned by [Interceptor.Validate] and lists the mismatches
+// between the configured policies and the registered services.
+type MethodValidationErr struct {
+	Unprotected []string
+	Unknown     []string
+}
+
+func (e *MethodValidationErr) Error() string {
+	parts := make([]string, 0, 2)
+	if len(e.Unprotected) > 0 {
+		parts = append(parts, "methods without policy: "+strings.Join(e.Unprotected, ", "))
+	}
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "policies without method: "+strings.Join(e.Unknown, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
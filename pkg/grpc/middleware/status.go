@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+const (
+	// ErrorDomain is the default domain of the [errdetails.ErrorInfo] attached by the [DefaultStatusMapper].
+	// Use [NewStatusMapper] to set the domain of your service instead.
+	ErrorDomain = "zitadel.com"
+
+	// DefaultRetryDelay is the delay of the [errdetails.RetryInfo] attached by the [DefaultStatusMapper]
+	// in case the authorization service is unavailable.
+	DefaultRetryDelay = time.Second
+
+	ReasonUnauthenticated    = "UNAUTHENTICATED"
+	ReasonPermissionDenied   = "PERMISSION_DENIED"
+	ReasonInsufficientScope  = "INSUFFICIENT_SCOPE"
+	ReasonNoPolicy           = "NO_POLICY"
+	ReasonServiceUnavailable = "SERVICE_UNAVAILABLE"
+)
+
+// StatusMapper converts the error of a failed authorization check of the method into the returned gRPC status.
+type StatusMapper func(ctx context.Context, method string, err error) *status.Status
+
+// WithStatusMapper allows to customize the gRPC status returned for a failed authorization check.
+// The default is the [DefaultStatusMapper].
+func WithStatusMapper[T authorization.Ctx](mapper StatusMapper) Option[T] {
+	return func(i *Interceptor[T]) {
+		i.statusMapper = mapper
+	}
+}
+
+// DefaultStatusMapper maps the authorization errors to the following codes:
+//   - [codes.Unauthenticated] for an [authorization.UnauthorizedErr]
+//   - [codes.Unavailable] for an [authorization.ServiceUnavailableErr] including an [errdetails.RetryInfo]
+//   - [codes.PermissionDenied] for any other error (e.g. [authorization.PermissionDeniedErr])
+//
+// Every status contains an [errdetails.ErrorInfo] of the [ErrorDomain] with the method and the failed checks as metadata.
+func DefaultStatusMapper(_ context.Context, method string, err error) *status.Status {
+	return mapStatus(ErrorDomain, method, err)
+}
+
+// NewStatusMapper creates a [StatusMapper] like the [DefaultStatusMapper],
+// which attaches the [errdetails.ErrorInfo] with the provided domain (e.g. `api.example.com`).
+func NewStatusMapper(domain string) StatusMapper {
+	return func(_ context.Context, method string, err error) *status.Status {
+		return mapStatus(domain, method, err)
+	}
+}
+
+func mapStatus(domain, method string, err error) *status.Status {
+	code, reason := codes.PermissionDenied, ReasonPermissionDenied
+	switch {
+	case errors.Is(err, &authorization.UnauthorizedErr{}):
+		code, reason = codes.Unauthenticated, ReasonUnauthenticated
+	case errors.Is(err, &authorization.ServiceUnavailableErr{}):
+		code, reason = codes.Unavailable, ReasonServiceUnavailable
+	case errors.Is(err, ErrNoPolicy):
+		reason = ReasonNoPolicy
+	case errors.Is(err, authorization.ErrMissingScope):
+		reason = ReasonInsufficientScope
+	}
+	info := &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   domain,
+		Metadata: errorMetadata(method, err),
+	}
+	st := status.New(code, err.Error())
+	if code == codes.Unavailable {
+		return withDetails(st, info, &errdetails.RetryInfo{RetryDelay: durationpb.New(DefaultRetryDelay)})
+	}
+	return withDetails(st, info)
+}
+
+// errorMetadata returns the method and if available, the failed checks and the required scopes.
+func errorMetadata(method string, err error) map[string]string {
+	metadata := map[string]string{"method": method}
+	var permissionDenied *authorization.PermissionDeniedErr
+	if errors.As(err, &permissionDenied) {
+		failed := permissionDenied.FailedChecks()
+		branches := make([]string, len(failed))
+		for i, check := range failed {
+			branches[i] = check.Branch
+		}
+		if len(branches) > 0 {
+			metadata["failed_checks"] = strings.Join(branches, ",")
+		}
+	}
+	var scopeErr *authorization.MissingScopeErr
+	if errors.As(err, &scopeErr) {
+		metadata["scope"] = strings.Join(scopeErr.Scopes, " ")
+	}
+	return metadata
+}
+
+// withDetails attaches the details to the status and returns the status without details if they cannot be attached.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+)
+
+// TestDefaultStatusMapper verifies the codes and attached details for the different authorization errors.
+func TestDefaultStatusMapper(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantCode     codes.Code
+		wantReason   string
+		wantMetadata map[string]string
+		wantRetry    bool
+	}{
+		{
+			name:         "unauthorized",
+			err:          authorization.NewErrorUnauthorized(authorization.ErrMissingToken),
+			wantCode:     codes.Unauthenticated,
+			wantReason:   middleware.ReasonUnauthenticated,
+			wantMetadata: map[string]string{"method": "/pkg.Service/Get"},
+		},
+		{
+			name:         "service unavailable",
+			err:          authorization.NewErrorServiceUnavailable(errors.New("introspection failed")),
+			wantCode:     codes.Unavailable,
+			wantReason:   middleware.ReasonServiceUnavailable,
+			wantMetadata: map[string]string{"method": "/pkg.Service/Get"},
+			wantRetry:    true,
+		},
+		{
+			name:         "missing scope",
+			err:          authorization.NewErrorPermissionDenied(&authorization.MissingScopeErr{Scopes: []string{"read"}}),
+			wantCode:     codes.PermissionDenied,
+			wantReason:   middleware.ReasonInsufficientScope,
+			wantMetadata: map[string]string{"method": "/pkg.Service/Get", "scope": "read"},
+		},
+		{
+			name:         "named check",
+			err:          authorization.NewErrorPermissionDenied(authorization.NewCheckErr("is owner", authorization.ErrCheckFailed)),
+			wantCode:     codes.PermissionDenied,
+			wantReason:   middleware.ReasonPermissionDenied,
+			wantMetadata: map[string]string{"method": "/pkg.Service/Get", "failed_checks": "is owner"},
+		},
+		{
+			name:         "no policy",
+			err:          authorization.NewErrorPermissionDenied(middleware.ErrNoPolicy),
+			wantCode:     codes.PermissionDenied,
+			wantReason:   middleware.ReasonNoPolicy,
+			wantMetadata: map[string]string{"method": "/pkg.Service/Get"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := middleware.DefaultStatusMapper(context.Background(), "/pkg.Service/Get", tt.err)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.err.Error(), st.Message())
+
+			var (
+				info  *errdetails.ErrorInfo
+				retry *errdetails.RetryInfo
+			)
+			for _, detail := range st.Details() {
+				switch d := detail.(type) {
+				case *errdetails.ErrorInfo:
+					info = d
+				case *errdetails.RetryInfo:
+					retry = d
+				}
+			}
+			require.NotNil(t, info)
+			assert.Equal(t, tt.wantReason, info.GetReason())
+			assert.Equal(t, middleware.ErrorDomain, info.GetDomain())
+			assert.Equal(t, tt.wantMetadata, info.GetMetadata())
+			assert.Equal(t, tt.wantRetry, retry != nil)
+		})
+	}
+}
+
+// TestNewStatusMapper verifies that the domain of the attached error info can be set.
+func TestNewStatusMapper(t *testing.T) {
+	st := middleware.NewStatusMapper("api.example.com")(context.Background(), "/pkg.Service/Get", authorization.NewErrorPermissionDenied(nil))
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "api.example.com", info.GetDomain())
+}
+
+// TestInterceptor_WithStatusMapper verifies that a custom status mapper is used for failed checks.
+func TestInterceptor_WithStatusMapper(t *testing.T) {
+	checker := &mockChecker{err: authorization.NewErrorServiceUnavailable(errors.New("introspection failed"))}
+	interceptor := middleware.New[*mockCtx](checker, map[string][]authorization.CheckOption{"/pkg.Service/*": nil},
+		middleware.WithStatusMapper[*mockCtx](func(_ context.Context, method string, err error) *status.Status {
+			return status.New(codes.Internal, method)
+		}),
+	)
+	_, err := interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		},
+	)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "/pkg.Service/Get", status.Convert(err).Message())
+}
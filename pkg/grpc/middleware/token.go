@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+// TokenExtractor returns the authorization of the incoming call in the form of an authorization header value
+// (e.g. `Bearer <token>`), which is then verified by the [authorization.AuthorizationChecker] including its scheme.
+// It returns an empty string if the call does not provide a token.
+type TokenExtractor func(ctx context.Context) string
+
+// WithTokenExtractor allows to customize where the token is taken from (e.g. [TokenFromMetadata] for proxied calls).
+// The default is [TokenFromAuthorizationMetadata].
+func WithTokenExtractor[T authorization.Ctx](extractor TokenExtractor) Option[T] {
+	return func(i *Interceptor[T]) {
+		i.tokenExtractor = extractor
+	}
+}
+
+// TokenFromAuthorizationMetadata returns the value of the `authorization` metadata including the scheme (e.g. `Bearer` or `DPoP`).
+func TokenFromAuthorizationMetadata() TokenExtractor {
+	return func(ctx context.Context) string {
+		return firstValue(ctx, authorization.HeaderName)
+	}
+}
+
+// TokenFromMetadata returns the (raw) access token of the provided metadata key (e.g. `x-forwarded-access-token`) as bearer token.
+func TokenFromMetadata(key string) TokenExtractor {
+	return func(ctx context.Context) string {
+		return bearerToken(firstValue(ctx, key))
+	}
+}
+
+// TokenFromCookie returns the access token of the provided cookie of the `cookie` metadata as bearer token,
+// e.g. for gRPC-Web calls of a browser.
+// Since browsers attach cookies to cross-site requests as well, calls authorized by the cookie are exposed to
+// cross-site request forgery (CSRF). Set the cookie with `SameSite=Strict` (or `Lax`) or require a custom header,
+// which cannot be set by a cross-site form, e.g. the `x-grpc-web` header sent by gRPC-Web clients.
+func TokenFromCookie(name string) TokenExtractor {
+	return func(ctx context.Context) string {
+		for _, header := range metadata.ValueFromIncomingContext(ctx, "cookie") {
+			cookies, err := http.ParseCookie(header)
+			if err != nil {
+				continue
+			}
+			for _, cookie := range cookies {
+				if cookie.Name == name {
+					return bearerToken(cookie.Value)
+				}
+			}
+		}
+		return ""
+	}
+}
+
+// ChainTokenExtractors returns the token of the first extractor providing one.
+func ChainTokenExtractors(extractors ...TokenExtractor) TokenExtractor {
+	return func(ctx context.Context) string {
+		for _, extractor := range extractors {
+			if token := extractor(ctx); token != "" {
+				return token
+			}
+		}
+		return ""
+	}
+}
+
+func firstValue(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func bearerToken(token string) string {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ""
+	}
+	return oidc.BearerToken + " " + token
+}
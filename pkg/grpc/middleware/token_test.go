@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/grpc/middleware"
+)
+
+// TestTokenExtractors verifies the token extraction of the single extractors and their chaining.
+func TestTokenExtractors(t *testing.T) {
+	extractor := middleware.ChainTokenExtractors(
+		middleware.TokenFromAuthorizationMetadata(),
+		middleware.TokenFromMetadata("x-forwarded-access-token"),
+		middleware.TokenFromCookie("access_token"),
+	)
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want string
+	}{
+		{
+			name: "authorization metadata",
+			md:   metadata.Pairs("authorization", "DPoP token", "x-forwarded-access-token", "forwarded"),
+			want: "DPoP token",
+		},
+		{
+			name: "custom metadata",
+			md:   metadata.Pairs("x-forwarded-access-token", "forwarded"),
+			want: "Bearer forwarded",
+		},
+		{
+			name: "cookie",
+			md:   metadata.Pairs("cookie", "theme=dark; access_token=cookie"),
+			want: "Bearer cookie",
+		},
+		{
+			name: "none",
+			md:   metadata.Pairs("cookie", "theme=dark"),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractor(metadata.NewIncomingContext(context.Background(), tt.md)))
+		})
+	}
+}
+
+// TestInterceptor_WithTokenExtractor verifies that the token of the configured extractor is checked.
+func TestInterceptor_WithTokenExtractor(t *testing.T) {
+	checker := &mockChecker{ctx: &mockCtx{}}
+	interceptor := middleware.New[*mockCtx](checker, map[string][]authorization.CheckOption{"/pkg.Service/*": nil},
+		middleware.WithTokenExtractor[*mockCtx](middleware.TokenFromMetadata("x-forwarded-access-token")),
+	)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-access-token", "valid-token"))
+	_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer valid-token", checker.token)
+}
@@ -2,32 +2,49 @@ package middleware
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
 )
 
 type Interceptor[T authorization.Ctx] struct {
-	authorizer authorization.AuthorizationChecker[T]
+	authorizer     authorization.AuthorizationChecker[T]
+	errorResponder ErrorResponder
+	tokenExtractor TokenExtractor
 }
 
-func New[T authorization.Ctx](authorizer authorization.AuthorizationChecker[T]) *Interceptor[T] {
-	return &Interceptor[T]{
-		authorizer: authorizer,
+// Option allows customization of the [Interceptor] such as the [ErrorResponder] or the [TokenExtractor].
+type Option[T authorization.Ctx] func(*Interceptor[T])
+
+// WithErrorResponder allows to customize the response of a failed authorization check
+// (e.g. [ProblemJSONErrorResponder]). The default is a [TextErrorResponder] without realm.
+func WithErrorResponder[T authorization.Ctx](responder ErrorResponder) Option[T] {
+	return func(i *Interceptor[T]) {
+		i.errorResponder = responder
+	}
+}
+
+func New[T authorization.Ctx](authorizer authorization.AuthorizationChecker[T], options ...Option[T]) *Interceptor[T] {
+	interceptor := &Interceptor[T]{
+		authorizer:     authorizer,
+		errorResponder: TextErrorResponder(""),
+		tokenExtractor: TokenFromAuthorizationHeader(),
+	}
+	for _, option := range options {
+		option(interceptor)
 	}
+	return interceptor
 }
 
+// RequireAuthorization creates a handler, which only calls the next handler if the authorization check succeeds.
+// Otherwise, the configured [ErrorResponder] is used to respond with 401, 403 or 503 depending on the error.
+// The [*http.Request] is provided to checks created by [authorization.WithRequestCheck].
 func (i *Interceptor[T]) RequireAuthorization(options ...authorization.CheckOption) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			ctx, err := i.authorizer.CheckAuthorization(req.Context(), req.Header.Get(authorization.HeaderName), options...)
+			ctx, err := i.authorizer.CheckAuthorization(authorization.WithRequest(req.Context(), req), i.tokenExtractor(req), options...)
 			if err != nil {
-				if errors.Is(err, &authorization.UnauthorizedErr{}) {
-					http.Error(w, err.Error(), http.StatusUnauthorized)
-					return
-				}
-				http.Error(w, err.Error(), http.StatusForbidden)
+				i.errorResponder(w, req, err)
 				return
 			}
 			req = req.WithContext(authorization.WithAuthContext(req.Context(), ctx))
@@ -39,7 +56,7 @@ func (i *Interceptor[T]) RequireAuthorization(options ...authorization.CheckOpti
 func (i *Interceptor[T]) CheckAuthorization(options ...authorization.CheckOption) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			ctx, err := i.authorizer.CheckAuthorization(req.Context(), req.Header.Get(authorization.HeaderName), options...)
+			ctx, err := i.authorizer.CheckAuthorization(authorization.WithRequest(req.Context(), req), i.tokenExtractor(req), options...)
 			if err == nil {
 				req = req.WithContext(authorization.WithAuthContext(req.Context(), ctx))
 			}
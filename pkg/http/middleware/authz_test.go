@@ -12,6 +12,7 @@ import (
 	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
 	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware"
 	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware/internal"
+	"github.com/zitadel/zitadel-go/v3/pkg/zitadel"
 )
 
 // TestInterceptor_RequireAuthorization_Success verifies that when authorization
@@ -320,3 +321,48 @@ func TestInterceptor_Context(t *testing.T) {
 	assert.Equal(t, "user-123", retrievedAuthCtx.UserID())
 	assert.Equal(t, "org-456", retrievedAuthCtx.OrganizationID())
 }
+
+// TestInterceptor_RequireAuthorization_RequestCheck verifies that the request is provided
+// to checks created by authorization.WithRequestCheck.
+func TestInterceptor_RequireAuthorization_RequestCheck(t *testing.T) {
+	authorizer, err := authorization.New(context.Background(), zitadel.New("zitadel.invalid"),
+		func(context.Context, *zitadel.Zitadel) (authorization.Verifier[*internal.MockAuthContext], error) {
+			return &internal.MockVerifier{Ctx: internal.NewMockAuthContext("user-123", "org-456")}, nil
+		},
+	)
+	require.NoError(t, err)
+	interceptor := middleware.New(authorizer)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	wrappedHandler := interceptor.RequireAuthorization(
+		authorization.WithRequestCheck("organization of path", func(_ context.Context, authCtx authorization.Ctx, req *http.Request) error {
+			if req.PathValue("org") != authCtx.OrganizationID() {
+				return errors.New("foreign organization")
+			}
+			return nil
+		}),
+	)(handler)
+	mux := http.NewServeMux()
+	mux.Handle("/orgs/{org}/projects", wrappedHandler)
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{path: "/orgs/org-456/projects", wantCode: http.StatusOK},
+		{path: "/orgs/org-789/projects", wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			request.Header.Set("Authorization", "Bearer valid-token")
+			response := httptest.NewRecorder()
+
+			mux.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.wantCode, response.Code)
+		})
+	}
+}
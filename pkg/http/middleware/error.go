@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+const (
+	// HeaderWWWAuthenticate is the response header used to inform the caller about the required authentication (RFC 6750).
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+
+	// ContentTypeProblemJSON is the content type of RFC 7807 problem details.
+	ContentTypeProblemJSON = "application/problem+json"
+
+	bearerErrorInvalidToken      = "invalid_token"
+	bearerErrorInsufficientScope = "insufficient_scope"
+	dpopErrorInvalidProof        = "invalid_dpop_proof"
+)
+
+// ErrorResponder writes the response for a failed authorization check of [Interceptor.RequireAuthorization].
+type ErrorResponder func(w http.ResponseWriter, req *http.Request, err error)
+
+// TextErrorResponder responds with the RFC 6750 `WWW-Authenticate` header and the error message as plain text body.
+// The realm is optional and will be omitted from the header if empty.
+// This is the default [ErrorResponder] of the [Interceptor].
+func TextErrorResponder(realm string) ErrorResponder {
+	return func(w http.ResponseWriter, _ *http.Request, err error) {
+		status := StatusCode(err)
+		setWWWAuthenticate(w, realm, err)
+		http.Error(w, err.Error(), status)
+	}
+}
+
+// ProblemJSONErrorResponder responds with the RFC 6750 `WWW-Authenticate` header and RFC 7807 problem details as JSON body.
+// The realm is optional and will be omitted from the header if empty.
+func ProblemJSONErrorResponder(realm string) ErrorResponder {
+	return func(w http.ResponseWriter, req *http.Request, err error) {
+		status := StatusCode(err)
+		setWWWAuthenticate(w, realm, err)
+		problem := &ProblemDetails{
+			Type:     "about:blank",
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   err.Error(),
+			Instance: req.URL.Path,
+		}
+		w.Header().Set("Content-Type", ContentTypeProblemJSON)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(problem)
+	}
+}
+
+// ProblemDetails represents an RFC 7807 problem details object.
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// StatusCode returns the HTTP status code for the provided authorization error:
+//   - 401 for an [authorization.UnauthorizedErr]
+//   - 503 for an [authorization.ServiceUnavailableErr]
+//   - 403 for any other error (e.g. [authorization.PermissionDeniedErr])
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, &authorization.UnauthorizedErr{}):
+		return http.StatusUnauthorized
+	case errors.Is(err, &authorization.ServiceUnavailableErr{}):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusForbidden
+	}
+}
+
+// WWWAuthenticate returns the RFC 6750 `WWW-Authenticate` header value for the provided authorization error.
+// It returns an empty string if no header should be sent (e.g. service unavailable).
+//   - missing token: `Bearer realm="..."` without an error code
+//   - invalid token: `Bearer realm="...", error="invalid_token"`
+//   - missing scope: `Bearer realm="...", error="insufficient_scope", scope="..."` with the required scopes
+//   - other permission denied (e.g. a missing role): `Bearer realm="..."` without an error code
+//   - invalid DPoP proof: `DPoP realm="...", error="invalid_dpop_proof"` (RFC 9449)
+//   - DPoP required: `DPoP realm="..."`
+func WWWAuthenticate(realm string, err error) string {
+	params := make([]string, 0, 3)
+	if realm != "" {
+		params = append(params, authParam("realm", realm))
+	}
+	switch {
+	case errors.Is(err, authorization.ErrInvalidDPoPProof):
+		params = append(params, authParam("error", dpopErrorInvalidProof))
+		return authorization.SchemeDPoP + " " + strings.Join(params, ", ")
+	case errors.Is(err, authorization.ErrDPoPRequired):
+		if len(params) == 0 {
+			return authorization.SchemeDPoP
+		}
+		return authorization.SchemeDPoP + " " + strings.Join(params, ", ")
+	case errors.Is(err, &authorization.UnauthorizedErr{}):
+		if !errors.Is(err, authorization.ErrMissingToken) {
+			params = append(params, authParam("error", bearerErrorInvalidToken))
+		}
+	case errors.Is(err, &authorization.ServiceUnavailableErr{}):
+		return ""
+	default:
+		var scopeErr *authorization.MissingScopeErr
+		if errors.As(err, &scopeErr) {
+			params = append(params, authParam("error", bearerErrorInsufficientScope), authParam("scope", strings.Join(scopeErr.Scopes, " ")))
+		}
+	}
+	if len(params) == 0 {
+		return oidc.BearerToken
+	}
+	return oidc.BearerToken + " " + strings.Join(params, ", ")
+}
+
+func setWWWAuthenticate(w http.ResponseWriter, realm string, err error) {
+	if header := WWWAuthenticate(realm, err); header != "" {
+		w.Header().Set(HeaderWWWAuthenticate, header)
+	}
+}
+
+// authParam formats a single auth-param with its value as quoted-string.
+func authParam(key, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", " ").Replace(value)
+	return key + `="` + value + `"`
+}
@@ -0,0 +1,153 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware"
+	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware/internal"
+)
+
+// TestWWWAuthenticate verifies the RFC 6750 header values for the different authorization errors.
+func TestWWWAuthenticate(t *testing.T) {
+	tests := []struct {
+		name  string
+		realm string
+		err   error
+		want  string
+	}{
+		{
+			name: "missing token without realm",
+			err:  authorization.NewErrorUnauthorized(authorization.ErrMissingToken),
+			want: `Bearer`,
+		},
+		{
+			name:  "missing token with realm",
+			realm: "api",
+			err:   authorization.NewErrorUnauthorized(authorization.ErrMissingToken),
+			want:  `Bearer realm="api"`,
+		},
+		{
+			name:  "invalid token",
+			realm: "api",
+			err:   authorization.NewErrorUnauthorized(errors.New("token expired")),
+			want:  `Bearer realm="api", error="invalid_token"`,
+		},
+		{
+			name:  "missing scope",
+			realm: "api",
+			err:   authorization.NewErrorPermissionDenied(&authorization.MissingScopeErr{Scopes: []string{"read", "write"}}),
+			want:  `Bearer realm="api", error="insufficient_scope", scope="read write"`,
+		},
+		{
+			name: "missing role",
+			err:  authorization.NewErrorPermissionDenied(authorization.ErrMissingRole),
+			want: `Bearer`,
+		},
+		{
+			name:  "no policy",
+			realm: "api",
+			err:   authorization.NewErrorPermissionDenied(middleware.ErrNoPolicy),
+			want:  `Bearer realm="api"`,
+		},
+		{
+			name:  "realm is escaped",
+			realm: `my "api"`,
+			err:   authorization.NewErrorUnauthorized(authorization.ErrMissingToken),
+			want:  `Bearer realm="my \"api\""`,
+		},
+		{
+			name: "service unavailable",
+			err:  authorization.NewErrorServiceUnavailable(errors.New("503")),
+			want: "",
+		},
+		{
+			name:  "invalid dpop proof",
+			realm: "api",
+			err:   authorization.NewErrorUnauthorized(fmt.Errorf("%w: %w", authorization.ErrInvalidDPoPProof, authorization.ErrDPoPProofReplayed)),
+			want:  `DPoP realm="api", error="invalid_dpop_proof"`,
+		},
+		{
+			name: "dpop required",
+			err:  authorization.NewErrorUnauthorized(fmt.Errorf("%w: %w", authorization.ErrMissingToken, authorization.ErrDPoPRequired)),
+			want: `DPoP`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, middleware.WWWAuthenticate(tt.realm, tt.err))
+		})
+	}
+}
+
+// TestInterceptor_RequireAuthorization_ErrorResponses verifies the status codes and
+// headers of the default and the problem+json error responder.
+func TestInterceptor_RequireAuthorization_ErrorResponses(t *testing.T) {
+	tests := []struct {
+		name            string
+		responder       middleware.ErrorResponder
+		err             error
+		wantStatus      int
+		wantHeader      string
+		wantContentType string
+	}{
+		{
+			name:            "default, unauthorized",
+			err:             authorization.NewErrorUnauthorized(errors.New("invalid token")),
+			wantStatus:      http.StatusUnauthorized,
+			wantHeader:      `Bearer error="invalid_token"`,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:            "default, service unavailable",
+			err:             authorization.NewErrorServiceUnavailable(errors.New("introspection failed")),
+			wantStatus:      http.StatusServiceUnavailable,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:            "problem json, insufficient scope",
+			responder:       middleware.ProblemJSONErrorResponder("api"),
+			err:             authorization.NewErrorPermissionDenied(&authorization.MissingScopeErr{Scopes: []string{"write"}}),
+			wantStatus:      http.StatusForbidden,
+			wantHeader:      `Bearer realm="api", error="insufficient_scope", scope="write"`,
+			wantContentType: middleware.ContentTypeProblemJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []middleware.Option[*internal.MockAuthContext]
+			if tt.responder != nil {
+				options = append(options, middleware.WithErrorResponder[*internal.MockAuthContext](tt.responder))
+			}
+			interceptor := middleware.New(&internal.MockAuthorizationChecker{Err: tt.err}, options...)
+
+			var handlerCalled bool
+			handler := interceptor.RequireAuthorization()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/api/protected", nil)
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			assert.False(t, handlerCalled)
+			assert.Equal(t, tt.wantStatus, response.Code)
+			assert.Equal(t, tt.wantHeader, response.Header().Get(middleware.HeaderWWWAuthenticate))
+			assert.Equal(t, tt.wantContentType, response.Header().Get("Content-Type"))
+			if tt.wantContentType == middleware.ContentTypeProblemJSON {
+				var problem middleware.ProblemDetails
+				require.NoError(t, json.NewDecoder(response.Body).Decode(&problem))
+				assert.Equal(t, tt.wantStatus, problem.Status)
+				assert.Equal(t, "/api/protected", problem.Instance)
+				assert.Equal(t, tt.err.Error(), problem.Detail)
+			}
+		})
+	}
+}
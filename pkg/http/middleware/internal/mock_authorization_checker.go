@@ -12,11 +12,28 @@ import (
 type MockAuthorizationChecker struct {
 	Ctx *MockAuthContext
 	Err error
+	// Token is the token of the last call.
+	Token string
+}
+
+// CheckAuthorization records the token and returns the pre-configured context or error.
+func (m *MockAuthorizationChecker) CheckAuthorization(_ context.Context, token string, _ ...authorization.CheckOption) (*MockAuthContext, error) {
+	m.Token = token
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Ctx, nil
+}
+
+// MockVerifier is a mock implementation of authorization.Verifier for testing the middleware
+// with a real authorization.Authorizer (e.g. to run the provided checks).
+type MockVerifier struct {
+	Ctx *MockAuthContext
+	Err error
 }
 
 // CheckAuthorization returns the pre-configured context or error.
-// The parameters are intentionally unused as this is a test mock.
-func (m *MockAuthorizationChecker) CheckAuthorization(_ context.Context, _ string, _ ...authorization.CheckOption) (*MockAuthContext, error) {
+func (m *MockVerifier) CheckAuthorization(_ context.Context, _ string) (*MockAuthContext, error) {
 	if m.Err != nil {
 		return nil, m.Err
 	}
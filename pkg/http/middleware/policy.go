@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+var (
+	ErrNoPolicy = errors.New("no authorization policy for route")
+)
+
+// RoutePolicies is a router-agnostic authorization policy table keyed by [http.ServeMux] patterns
+// (e.g. `GET /api/tasks/{id}`). Each request is matched against the patterns the same way
+// the [http.ServeMux] does and the checks of the matching pattern are enforced.
+// Requests not matching any pattern are denied.
+// Use [Interceptor.RoutePolicies] for creation.
+type RoutePolicies[T authorization.Ctx] struct {
+	interceptor *Interceptor[T]
+	mux         *http.ServeMux
+	checks      map[string][]authorization.CheckOption
+	public      map[string]struct{}
+}
+
+// RoutePolicies creates a [RoutePolicies] table enforcing the provided checks per pattern.
+// Patterns listed as public are accessible without any authorization.
+// An error is returned if a pattern is invalid, conflicts with another or is listed more than once.
+func (i *Interceptor[T]) RoutePolicies(checks map[string][]authorization.CheckOption, public ...string) (*RoutePolicies[T], error) {
+	policies := &RoutePolicies[T]{
+		interceptor: i,
+		mux:         http.NewServeMux(),
+		checks:      checks,
+		public:      make(map[string]struct{}, len(public)),
+	}
+	for pattern := range checks {
+		if err := policies.register(pattern); err != nil {
+			return nil, err
+		}
+	}
+	for _, pattern := range public {
+		if _, ok := checks[pattern]; ok {
+			return nil, fmt.Errorf("route `%s` is listed as public and has checks", pattern)
+		}
+		if err := policies.register(pattern); err != nil {
+			return nil, err
+		}
+		policies.public[pattern] = struct{}{}
+	}
+	return policies, nil
+}
+
+// register adds the pattern to the underlying [http.ServeMux], which panics on invalid or conflicting patterns.
+func (p *RoutePolicies[T]) register(pattern string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid route pattern `%s`: %v", pattern, r)
+		}
+	}()
+	p.mux.Handle(pattern, routeHandler{})
+	return nil
+}
+
+// routeHandler marks the registered patterns, so they can be distinguished
+// from the handlers generated by the [http.ServeMux] (e.g. to redirect to the canonical path).
+type routeHandler struct{}
+
+func (routeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	http.NotFound(w, req)
+}
+
+// Handler creates a handler enforcing the policy of the matching route before calling the next handler.
+// Public routes are passed through, protected routes require a successful authorization check
+// and unmatched routes are denied with an [ErrNoPolicy].
+// Requests, which the [http.ServeMux] would redirect (e.g. a path that is not in its canonical form),
+// are redirected without calling the next handler, so the policy always matches the path handled by the application.
+func (p *RoutePolicies[T]) Handler(next http.Handler) http.Handler {
+	handlers := make(map[string]http.Handler, len(p.checks)+len(p.public))
+	for pattern, checks := range p.checks {
+		handlers[pattern] = p.interceptor.RequireAuthorization(checks...)(next)
+	}
+	for pattern := range p.public {
+		handlers[pattern] = next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		muxHandler, pattern := p.mux.Handler(req)
+		if _, ok := muxHandler.(routeHandler); !ok && pattern != "" {
+			muxHandler.ServeHTTP(w, req)
+			return
+		}
+		handler, ok := handlers[pattern]
+		if !ok {
+			p.interceptor.errorResponder(w, req, authorization.NewErrorPermissionDenied(fmt.Errorf("%w: %s %s", ErrNoPolicy, req.Method, req.URL.Path)))
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Validate cross-checks the policy table against the patterns registered on the router of the application.
+// It returns a [RouteValidationErr] listing the routes without a policy (unprotected)
+// and the policies without a registered route (unknown), e.g. to fail at startup.
+func (p *RoutePolicies[T]) Validate(routes ...string) error {
+	err := new(RouteValidationErr)
+	registered := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		registered[route] = struct{}{}
+		if _, ok := p.checks[route]; ok {
+			continue
+		}
+		if _, ok := p.public[route]; ok {
+			continue
+		}
+		err.Unprotected = append(err.Unprotected, route)
+	}
+	for pattern := range p.checks {
+		if _, ok := registered[pattern]; !ok {
+			err.Unknown = append(err.Unknown, pattern)
+		}
+	}
+	for pattern := range p.public {
+		if _, ok := registered[pattern]; !ok {
+			err.Unknown = append(err.Unknown, pattern)
+		}
+	}
+	if len(err.Unprotected) == 0 && len(err.Unknown) == 0 {
+		return nil
+	}
+	slices.Sort(err.Unprotected)
+	slices.Sort(err.Unknown)
+	return err
+}
+
+// RouteValidationErr is returned by [RoutePolicies.Validate] and lists the mismatches
+// between the policy table and the registered routes.
+type RouteValidationErr struct {
+	Unprotected []string
+	Unknown     []string
+}
+
+func (e *RouteValidationErr) Error() string {
+	parts := make([]string, 0, 2)
+	if len(e.Unprotected) > 0 {
+		parts = append(parts, "routes without policy: "+strings.Join(e.Unprotected, ", "))
+	}
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "policies without route: "+strings.Join(e.Unknown, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,138 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware"
+	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware/internal"
+)
+
+// TestRoutePolicies_Handler verifies that protected, public and unmatched routes
+// are handled according to the policy table.
+func TestRoutePolicies_Handler(t *testing.T) {
+	tests := []struct {
+		name        string
+		checker     *internal.MockAuthorizationChecker
+		method      string
+		path        string
+		wantStatus  int
+		wantHandler bool
+	}{
+		{
+			name:        "protected route, authorized",
+			checker:     &internal.MockAuthorizationChecker{Ctx: internal.NewMockAuthContext("user-123", "org-456")},
+			method:      http.MethodGet,
+			path:        "/api/tasks/1",
+			wantStatus:  http.StatusOK,
+			wantHandler: true,
+		},
+		{
+			name:       "protected route, unauthorized",
+			checker:    &internal.MockAuthorizationChecker{Err: authorization.NewErrorUnauthorized(errors.New("invalid token"))},
+			method:     http.MethodGet,
+			path:       "/api/tasks/1",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "public route",
+			checker:     &internal.MockAuthorizationChecker{Err: authorization.NewErrorUnauthorized(errors.New("invalid token"))},
+			method:      http.MethodGet,
+			path:        "/api/healthz",
+			wantStatus:  http.StatusOK,
+			wantHandler: true,
+		},
+		{
+			name:       "unmatched method is denied",
+			checker:    &internal.MockAuthorizationChecker{Ctx: internal.NewMockAuthContext("user-123", "org-456")},
+			method:     http.MethodDelete,
+			path:       "/api/tasks/1",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unmatched path is denied",
+			checker:    &internal.MockAuthorizationChecker{Ctx: internal.NewMockAuthContext("user-123", "org-456")},
+			method:     http.MethodGet,
+			path:       "/api/unknown",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := middleware.New(tt.checker).RoutePolicies(map[string][]authorization.CheckOption{
+				"GET /api/tasks/{id}": {authorization.WithRole("reader")},
+				"POST /api/tasks":     {authorization.WithRole("admin")},
+			}, "GET /api/healthz")
+			require.NoError(t, err)
+
+			var handlerCalled bool
+			handler := policies.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			request.Header.Set("Authorization", "Bearer token")
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.wantStatus, response.Code)
+			assert.Equal(t, tt.wantHandler, handlerCalled)
+		})
+	}
+}
+
+// TestRoutePolicies_Handler_UncleanPath verifies that a path, which is not in its canonical form,
+// is redirected instead of being passed to the next handler with the policy of the cleaned path.
+func TestRoutePolicies_Handler_UncleanPath(t *testing.T) {
+	policies, err := middleware.New(&internal.MockAuthorizationChecker{}).RoutePolicies(nil, "GET /api/healthz/{path...}")
+	require.NoError(t, err)
+	handler := policies.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler must not be called")
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/api/tasks/../healthz/live", nil))
+	assert.GreaterOrEqual(t, response.Code, http.StatusMultipleChoices)
+	assert.Less(t, response.Code, http.StatusBadRequest)
+	assert.Equal(t, "/api/healthz/live", response.Header().Get("Location"))
+}
+
+// TestInterceptor_RoutePolicies_InvalidPattern verifies that invalid and
+// conflicting patterns are reported on creation.
+func TestInterceptor_RoutePolicies_InvalidPattern(t *testing.T) {
+	interceptor := middleware.New(&internal.MockAuthorizationChecker{})
+
+	_, err := interceptor.RoutePolicies(map[string][]authorization.CheckOption{
+		"GET /api/{id": nil,
+	})
+	assert.Error(t, err)
+
+	_, err = interceptor.RoutePolicies(map[string][]authorization.CheckOption{
+		"GET /api/tasks": nil,
+	}, "GET /api/tasks")
+	assert.Error(t, err)
+}
+
+// TestRoutePolicies_Validate verifies that routes without policy and policies
+// without route are reported.
+func TestRoutePolicies_Validate(t *testing.T) {
+	policies, err := middleware.New(&internal.MockAuthorizationChecker{}).RoutePolicies(map[string][]authorization.CheckOption{
+		"GET /api/tasks":  nil,
+		"POST /api/tasks": nil,
+	}, "GET /api/healthz")
+	require.NoError(t, err)
+
+	assert.NoError(t, policies.Validate("GET /api/tasks", "POST /api/tasks", "GET /api/healthz"))
+
+	err = policies.Validate("GET /api/tasks", "PUT /api/tasks/{id}", "GET /api/healthz", "DELETE /api/tasks/{id}")
+	var validationErr *middleware.RouteValidationErr
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{"DELETE /api/tasks/{id}", "PUT /api/tasks/{id}"}, validationErr.Unprotected)
+	assert.Equal(t, []string{"POST /api/tasks"}, validationErr.Unknown)
+}
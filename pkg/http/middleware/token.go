@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/authorization"
+)
+
+// TokenExtractor returns the authorization of the request in the form of an authorization header value
+// (e.g. `Bearer <token>`), which is then verified by the [authorization.AuthorizationChecker] including its scheme.
+// It returns an empty string if the request does not provide a token.
+type TokenExtractor func(req *http.Request) string
+
+// WithTokenExtractor allows to customize where the token is taken from (e.g. [TokenFromCookie] for browser requests).
+// The default is [TokenFromAuthorizationHeader].
+func WithTokenExtractor[T authorization.Ctx](extractor TokenExtractor) Option[T] {
+	return func(i *Interceptor[T]) {
+		i.tokenExtractor = extractor
+	}
+}
+
+// TokenFromAuthorizationHeader returns the value of the `Authorization` header including the scheme (e.g. `Bearer` or `DPoP`).
+func TokenFromAuthorizationHeader() TokenExtractor {
+	return func(req *http.Request) string {
+		return req.Header.Get(authorization.HeaderName)
+	}
+}
+
+// TokenFromHeader returns the (raw) access token of the provided header (e.g. `X-Forwarded-Access-Token`) as bearer token.
+func TokenFromHeader(name string) TokenExtractor {
+	return func(req *http.Request) string {
+		return bearerToken(req.Header.Get(name))
+	}
+}
+
+// TokenFromCookie returns the access token of the provided cookie as bearer token.
+// Browsers send the cookie with cross-site requests too, so handlers using it are exposed to
+// cross-site request forgery (CSRF), especially for state-changing methods (e.g. `POST`).
+// Mitigate this by setting the cookie with `SameSite=Strict` (or `Lax` for safe methods only)
+// or by requiring a custom header (e.g. `X-Requested-With`), which a cross-site form or link cannot set.
+func TokenFromCookie(name string) TokenExtractor {
+	return func(req *http.Request) string {
+		cookie, err := req.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return bearerToken(cookie.Value)
+	}
+}
+
+// TokenFromQuery returns the access token of the provided query parameter as bearer token,
+// e.g. for WebSocket upgrades or server-sent events, where browsers are not able to set headers.
+// Since URLs are likely to be logged, the extraction is restricted to the provided paths (exact match).
+func TokenFromQuery(parameter string, paths ...string) TokenExtractor {
+	return func(req *http.Request) string {
+		if !slices.Contains(paths, req.URL.Path) {
+			return ""
+		}
+		return bearerToken(req.URL.Query().Get(parameter))
+	}
+}
+
+// ChainTokenExtractors returns the token of the first extractor providing one.
+func ChainTokenExtractors(extractors ...TokenExtractor) TokenExtractor {
+	return func(req *http.Request) string {
+		for _, extractor := range extractors {
+			if token := extractor(req); token != "" {
+				return token
+			}
+		}
+		return ""
+	}
+}
+
+func bearerToken(token string) string {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ""
+	}
+	return oidc.BearerToken + " " + token
+}
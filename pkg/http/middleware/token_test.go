@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware"
+	"github.com/zitadel/zitadel-go/v3/pkg/http/middleware/internal"
+)
+
+// TestTokenExtractors verifies the token extraction of the single extractors and their chaining.
+func TestTokenExtractors(t *testing.T) {
+	extractor := middleware.ChainTokenExtractors(
+		middleware.TokenFromAuthorizationHeader(),
+		middleware.TokenFromHeader("X-Forwarded-Access-Token"),
+		middleware.TokenFromCookie("access_token"),
+		middleware.TokenFromQuery("access_token", "/events"),
+	)
+	tests := []struct {
+		name    string
+		request func() *http.Request
+		want    string
+	}{
+		{
+			name: "authorization header",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api", nil)
+				req.Header.Set("Authorization", "DPoP token")
+				req.Header.Set("X-Forwarded-Access-Token", "forwarded")
+				return req
+			},
+			want: "DPoP token",
+		},
+		{
+			name: "custom header",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api", nil)
+				req.Header.Set("X-Forwarded-Access-Token", "forwarded")
+				return req
+			},
+			want: "Bearer forwarded",
+		},
+		{
+			name: "cookie",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/api", nil)
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: "cookie"})
+				return req
+			},
+			want: "Bearer cookie",
+		},
+		{
+			name: "query of allowed path",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/events?access_token=query", nil)
+			},
+			want: "Bearer query",
+		},
+		{
+			name: "query of other path",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api?access_token=query", nil)
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractor(tt.request()))
+		})
+	}
+}
+
+// TestInterceptor_WithTokenExtractor verifies that the token of the configured extractor is checked.
+func TestInterceptor_WithTokenExtractor(t *testing.T) {
+	checker := &internal.MockAuthorizationChecker{
+		Ctx: internal.NewMockAuthContext("user-123", "org-456"),
+	}
+	interceptor := middleware.New(checker, middleware.WithTokenExtractor[*internal.MockAuthContext](middleware.TokenFromCookie("access_token")))
+	handler := interceptor.RequireAuthorization()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/protected", nil)
+	request.AddCookie(&http.Cookie{Name: "access_token", Value: "valid-token"})
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "Bearer valid-token", checker.Token)
+}